@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"crypto-alert-bot/internal/adapters/chat"
+	"crypto-alert-bot/internal/adapters/email"
+	"crypto-alert-bot/internal/adapters/logger"
+	"crypto-alert-bot/internal/adapters/postgres"
+	"crypto-alert-bot/internal/adapters/watchlist"
+	"crypto-alert-bot/internal/adapters/webhook"
+	"crypto-alert-bot/internal/services"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// newFanOutPublisher builds the publisher of every alert channel: the log, the -webhook flag URLs and the watchlist channels
+func newFanOutPublisher(ctx context.Context, channels map[string]watchlist.Channel, webhookURLs []string, retryPolicy services.RetryPolicy,
+	repo *postgres.Postgres, timeout time.Duration) (*services.FanOutPublisher, error) {
+	fanOut := services.NewFanOutPublisher(timeout)
+
+	err := fanOut.Add(watchlist.LogChannel, logger.NewTickerPublisher())
+	if err != nil {
+		return nil, err
+	}
+
+	if len(webhookURLs) > 0 {
+		endpoints := make([]webhook.Endpoint, 0, len(webhookURLs))
+		for _, url := range webhookURLs {
+			endpoints = append(endpoints, webhook.Endpoint{URL: url, Secret: os.Getenv("WEBHOOK_SECRET")})
+		}
+
+		webhookPublisher := webhook.NewPublisher(nil, endpoints, retryPolicy, repo)
+		go webhookPublisher.Run(ctx)
+
+		if err = fanOut.Add(watchlist.WebhooksChannel, webhookPublisher); err != nil {
+			return nil, err
+		}
+	}
+
+	names := make([]string, 0, len(channels))
+	for name := range channels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		channel := channels[name]
+
+		var publisher services.AlertPublisher
+
+		switch channel.Type {
+		case "webhook":
+			webhookPublisher := webhook.NewPublisher(nil, []webhook.Endpoint{{URL: channel.URL, Secret: os.Getenv(channel.SecretEnv)}}, retryPolicy, repo)
+			go webhookPublisher.Run(ctx)
+
+			publisher = webhookPublisher
+		case "slack":
+			publisher = chat.NewSlackPublisher(nil, channel.URL)
+		case "discord":
+			publisher = chat.NewDiscordPublisher(nil, channel.URL)
+		case "telegram":
+			publisher = chat.NewTelegramPublisher(nil, channel.URL, os.Getenv(channel.TokenEnv), channel.ChatID)
+		case "email":
+			emailPublisher := email.NewPublisher(email.Config{
+				Addr:     channel.SMTPAddr,
+				Username: channel.Username,
+				Password: os.Getenv(channel.PasswordEnv),
+				From:     channel.From,
+				StartTLS: channel.StartTLS,
+			}, channel.To, time.Duration(channel.Digest)*time.Second)
+			go emailPublisher.Run(ctx)
+
+			publisher = emailPublisher
+		default:
+			return nil, errors.Errorf("channel %q has unsupported type %q", name, channel.Type)
+		}
+
+		if err = fanOut.Add(name, publisher); err != nil {
+			return nil, err
+		}
+	}
+
+	return fanOut, nil
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"context"
+	"crypto-alert-bot/config"
+	"crypto-alert-bot/internal/adapters/postgres"
+	"crypto-alert-bot/internal/models"
+	"crypto-alert-bot/internal/services"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// historyCommand prints the stored alerts matching the filter flags, or their counts per pair and period with -aggregate
+func historyCommand(args []string) int {
+	flags := flag.NewFlagSet("history", flag.ContinueOnError)
+	pair := flags.String("pair", "", "only show alerts for this pair (e.g. BTCUSD)")
+	ruleType := flags.String("type", "", "only show alerts of this rule type (e.g. threshold, level, depeg)")
+	direction := flags.String("direction", "", "only show alerts moving in this direction (up, down or none)")
+	minChange := flags.Float64("min-change", 0, "only show alerts whose percentage change is at least this large on either direction")
+	since := flags.Duration("since", 0, "only show alerts from this long ago (e.g. 24h), instead of -from")
+	from := flags.String("from", "", "only show alerts from this time, in RFC3339 (e.g. 2024-05-01T00:00:00Z)")
+	to := flags.String("to", "", "only show alerts before this time, in RFC3339")
+	limit := flags.Int("limit", models.DefaultPageLimit, fmt.Sprintf("maximum number of alerts to show, at most %d", models.MaxPageLimit))
+	offset := flags.Int("offset", 0, "number of matching alerts to skip, newest first")
+	aggregate := flags.String("aggregate", "", "count the alerts per pair and period instead (hour or day)")
+
+	if err := flags.Parse(args); err != nil {
+		return parseFailure(err)
+	}
+
+	if *limit <= 0 {
+		return fail("history", errors.New("-limit must be positive"))
+	}
+
+	filter, err := historyFilter(*pair, *ruleType, *direction, *minChange, *since, *from, *to, time.Now())
+	if err != nil {
+		return fail("history", err)
+	}
+
+	page, err := models.Page{Limit: *limit, Offset: *offset}.Normalize()
+	if err != nil {
+		return fail("history", err)
+	}
+
+	var size models.BucketSize
+	if *aggregate != "" {
+		if size, err = models.ParseBucketSize(*aggregate); err != nil {
+			return fail("history", err)
+		}
+	}
+
+	loadDbConfigs := config.LoadDatabaseConfig()
+
+	db, err := config.ConnectToDatabase(loadDbConfigs)
+	if err != nil {
+		return fail("error on initializing db connection", err)
+	}
+	defer db.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var reader services.AlertReader = postgres.NewPostgres(db, loadDbConfigs.Schema, loadDbConfigs.TableConfigs, loadDbConfigs.TableAlerts)
+
+	if size != "" {
+		buckets, err := reader.AggregateAlerts(ctx, filter, size)
+		if err != nil {
+			return fail("error aggregating alerts", err)
+		}
+
+		printBuckets(buckets, size)
+
+		return exitOK
+	}
+
+	result, err := reader.QueryAlerts(ctx, filter, page)
+	if err != nil {
+		return fail("error querying alerts", err)
+	}
+
+	printAlerts(result)
+
+	return exitOK
+}
+
+// historyFilter builds the alert filter of the history flags, -since is relative to now and can't be combined with -from
+func historyFilter(pair, ruleType, direction string, minChange float64, since time.Duration, from, to string, now time.Time) (models.AlertFilter, error) {
+	filter := models.AlertFilter{
+		Pair:          strings.ToUpper(pair),
+		RuleType:      models.AlertType(strings.ToLower(ruleType)),
+		Direction:     models.Direction(strings.ToLower(direction)),
+		MinPercChange: minChange,
+	}
+
+	if since < 0 {
+		return models.AlertFilter{}, errors.New("-since can't be negative")
+	}
+
+	if since > 0 && from != "" {
+		return models.AlertFilter{}, errors.New("-since and -from can't be combined")
+	}
+
+	if since > 0 {
+		filter.From = now.Add(-since)
+	}
+
+	var err error
+
+	if from != "" {
+		if filter.From, err = time.Parse(time.RFC3339, from); err != nil {
+			return models.AlertFilter{}, errors.Wrap(err, "invalid -from")
+		}
+	}
+
+	if to != "" {
+		if filter.To, err = time.Parse(time.RFC3339, to); err != nil {
+			return models.AlertFilter{}, errors.Wrap(err, "invalid -to")
+		}
+	}
+
+	if err = filter.Validate(); err != nil {
+		return models.AlertFilter{}, err
+	}
+
+	return filter, nil
+}
+
+// printAlerts prints a page of alerts as a table, followed by the range of the page within the matching alerts
+func printAlerts(result models.AlertPage) {
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "TIME\tPAIR\tRULE\tDIRECTION\tPRICE CHANGE\tPERC CHANGE\tFINAL PRICE\tSOURCE\tTHRESHOLD")
+
+	for _, alert := range result.Alerts {
+		threshold := fmt.Sprintf("%v%%", alert.Config.PercOscillation)
+		if alert.Config.ZScore > 0 {
+			threshold = fmt.Sprintf("%vσ", alert.Config.ZScore)
+		}
+
+		switch {
+		case alert.RuleType == models.AlertLevel:
+			threshold = fmt.Sprintf("%v", alert.Target)
+		case len(alert.Indicators) > 0:
+			threshold = fmt.Sprintf("%s (%s)", alert.Expression, alert.Indicators)
+		case alert.Expression != "":
+			threshold = alert.Expression
+		case alert.Config.Window > 0:
+			threshold += " in " + alert.Config.Window.String()
+		}
+
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%v\t%.4f%%\t%v\t%s\t%s\n",
+			alert.Timestamp.Format(time.RFC3339), alert.Pair, alert.RuleType, alert.Direction, alert.PriceChange, alert.PercChange, alert.FinalPrice,
+			alert.PriceSource, threshold)
+	}
+
+	writer.Flush()
+
+	if len(result.Alerts) == 0 {
+		fmt.Printf("\nno alerts out of %d matching\n", result.Total)
+		return
+	}
+
+	fmt.Printf("\nalerts %d-%d of %d", result.Page.Offset+1, result.Page.Offset+len(result.Alerts), result.Total)
+	if result.HasMore() {
+		fmt.Printf(", next page with -offset %d", result.Page.Offset+len(result.Alerts))
+	}
+	fmt.Println()
+}
+
+// printBuckets prints the alert counts per pair and bucket as a table
+func printBuckets(buckets []models.AlertBucket, size models.BucketSize) {
+	layout := time.DateOnly
+	if size == models.BucketHour {
+		layout = "2006-01-02 15:00"
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "PERIOD\tPAIR\tALERTS\tMAX MOVE")
+
+	for _, bucket := range buckets {
+		fmt.Fprintf(writer, "%s\t%s\t%d\t%.4f%%\n", bucket.Start.Format(layout), bucket.Pair, bucket.Count, bucket.MaxPercChange)
+	}
+
+	writer.Flush()
+}
@@ -1,97 +1,93 @@
 package main
 
 import (
-	"context"
-	"crypto-alert-bot/config"
-	"crypto-alert-bot/internal/adapters/api"
-	"crypto-alert-bot/internal/adapters/logger"
-	"crypto-alert-bot/internal/adapters/postgres"
-	"crypto-alert-bot/internal/adapters/prompt"
-	"crypto-alert-bot/internal/models"
-	"crypto-alert-bot/internal/services"
+	"flag"
 	"fmt"
-	"log"
 	"os"
-	"os/signal"
-	"sync"
-	"syscall"
-	"time"
-)
-
-func main() {
-	loadDbConfigs := config.LoadDatabaseConfig()
+	"strings"
 
-	db, err := config.ConnectToDatabase(loadDbConfigs)
-	if err != nil {
-		log.Fatal("error on initializing db connection", err)
-	}
-	defer db.Close()
-
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+	"github.com/pkg/errors"
+)
 
-	repo := postgres.NewPostgres(db, loadDbConfigs.Schema, loadDbConfigs.TableConfigs, loadDbConfigs.TableAlerts)
+const (
+	exitOK      = 0
+	exitFailure = 1
+	exitUsage   = 2
+)
 
-	upholdApi := api.NewUpholdApi(nil)
+// command represents a bot subcommand
+type command struct {
+	name        string
+	description string
+	run         func(args []string) int
+}
 
-	publisher := logger.NewTickerPublisher()
+var commands = []command{
+	{name: "run", description: "start the schedulers for the given tickers", run: runCommand},
+	{name: "validate", description: "check a watchlist against the API and the rate limit without starting", run: validateCommand},
+	{name: "pairs", description: "list and search the available trading pairs", run: pairsCommand},
+	{name: "history", description: "query the stored alerts", run: historyCommand},
+	{name: "migrate", description: "apply the embedded database schema migrations", run: migrateCommand},
+}
 
-	tickers := prompt.AskUserInput(upholdApi)
+func main() {
+	os.Exit(dispatch(os.Args[1:]))
+}
 
-	var wg sync.WaitGroup
+// dispatch runs the subcommand named on the first argument, defaulting to run when none is given
+func dispatch(args []string) int {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		if len(args) > 0 && (args[0] == "-h" || args[0] == "--help") {
+			usage()
+			return exitOK
+		}
 
-	wg.Add(len(*tickers))
+		return runCommand(args)
+	}
 
-	fmt.Println("Starting bot")
+	if args[0] == "help" {
+		usage()
+		return exitOK
+	}
 
-	for _, t := range *tickers {
-		go runSchedulerBot(ctx, &wg, *t, upholdApi, repo, publisher)
+	for _, cmd := range commands {
+		if cmd.name == args[0] {
+			return cmd.run(args[1:])
+		}
 	}
 
-	go gracefulShutdown(cancel)
+	fmt.Fprintf(os.Stderr, "unknown command %q\n\n", args[0])
+	usage()
 
-	wg.Wait()
+	return exitUsage
 }
 
-func runSchedulerBot(ctx context.Context, wg *sync.WaitGroup, ticker models.Ticker, upholdApi *api.UpholdApi, repo *postgres.Postgres, publisher services.Publisher) {
-	defer wg.Done()
-
-	tickerScheduler := services.NewTickerScheduler(upholdApi, &ticker, repo, publisher)
-
-	tickerScheduler.SchedulerStart(ctx)
-
-	if ticker.Config.Lifetime > 0 {
-		select {
-		case <-time.After(ticker.Config.Lifetime * time.Second):
-
-			tickerScheduler.SchedulerStop()
-
-			fmt.Printf("Scheduler for %s completed", ticker.Pair)
-		case <-ctx.Done():
-			fmt.Println("Shutting down scheduler for", ticker.Pair)
-
-			tickerScheduler.SchedulerStop()
-		}
-	} else {
-		select {
-		case <-ctx.Done():
-			fmt.Println("Shutting down scheduler for", ticker.Pair)
-
-			tickerScheduler.SchedulerStop()
+// usage prints the available subcommands
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: bot <command> [flags]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Commands:")
 
-			return
-		}
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", cmd.name, cmd.description)
 	}
-}
 
-func gracefulShutdown(cancel context.CancelFunc) {
-	sigChan := make(chan os.Signal, 1)
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Run 'bot <command> -h' for the flags of each command")
+}
 
-	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+// fail prints the error to stderr and returns the failure exit code
+func fail(msg string, err error) int {
+	fmt.Fprintf(os.Stderr, "%s: %v\n", msg, err)
 
-	<-sigChan
+	return exitFailure
+}
 
-	fmt.Println("Shutting down...")
+// parseFailure returns the exit code for a flag parsing error, asking for help is not a failure
+func parseFailure(err error) int {
+	if errors.Is(err, flag.ErrHelp) {
+		return exitOK
+	}
 
-	cancel()
+	return exitUsage
 }
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"crypto-alert-bot/config"
+	"crypto-alert-bot/internal/adapters/postgres"
+	"crypto-alert-bot/migrations"
+	"database/sql"
+	"flag"
+	"fmt"
+	"time"
+)
+
+var migrateTimeout = 2 * time.Minute
+
+// migrateCommand applies the embedded schema migrations to the database
+func migrateCommand(args []string) int {
+	flags := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	status := flags.Bool("status", false, "only list the pending migrations, without applying them")
+
+	if err := flags.Parse(args); err != nil {
+		return parseFailure(err)
+	}
+
+	loadDbConfigs := config.LoadDatabaseConfig()
+
+	db, err := config.ConnectToDatabase(loadDbConfigs)
+	if err != nil {
+		return fail("error on initializing db connection", err)
+	}
+	defer db.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), migrateTimeout)
+	defer cancel()
+
+	if *status {
+		migrator, err := postgres.NewMigrator(db, migrations.FS, loadDbConfigs.Schema, loadDbConfigs.TableConfigs, loadDbConfigs.TableAlerts)
+		if err != nil {
+			return fail("error loading migrations", err)
+		}
+
+		pending, err := migrator.Pending(ctx)
+		if err != nil {
+			return fail("error checking migrations", err)
+		}
+
+		fmt.Printf("%d pending migration(s)\n", len(pending))
+
+		for _, migration := range pending {
+			fmt.Printf("  V%d %s\n", migration.Version, migration.Description)
+		}
+
+		return exitOK
+	}
+
+	if err = migrate(ctx, db, loadDbConfigs); err != nil {
+		return fail("error applying migrations", err)
+	}
+
+	return exitOK
+}
+
+// migrate applies the pending embedded migrations to the configured schema and tables and prints the applied ones
+func migrate(ctx context.Context, db *sql.DB, dbConfigs *config.DatabaseConfig) error {
+	migrator, err := postgres.NewMigrator(db, migrations.FS, dbConfigs.Schema, dbConfigs.TableConfigs, dbConfigs.TableAlerts)
+	if err != nil {
+		return err
+	}
+
+	applied, err := migrator.Migrate(ctx)
+
+	for _, migration := range applied {
+		fmt.Printf("Applied migration V%d %s\n", migration.Version, migration.Description)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if len(applied) == 0 {
+		fmt.Println("Database schema is up to date")
+	}
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"crypto-alert-bot/internal/adapters/api"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// pairsCommand lists the trading pairs available on the API, optionally filtered
+func pairsCommand(args []string) int {
+	flags := flag.NewFlagSet("pairs", flag.ContinueOnError)
+	search := flags.String("search", "", "only show pairs containing this text (e.g. BTC)")
+	currency := flags.String("currency", "", "only show pairs quoted in this currency (e.g. USD)")
+
+	if err := flags.Parse(args); err != nil {
+		return parseFailure(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	tickers, err := api.NewUpholdApi(nil).FetchAllTickers(ctx)
+	if err != nil {
+		return fail("error listing pairs", err)
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "PAIR\tCURRENCY\tASK\tBID")
+
+	found := 0
+
+	for _, ticker := range tickers {
+		if *search != "" && !strings.Contains(ticker.Pair, strings.ToUpper(*search)) {
+			continue
+		}
+
+		if *currency != "" && !strings.EqualFold(ticker.Currency, *currency) {
+			continue
+		}
+
+		fmt.Fprintf(writer, "%s\t%s\t%v\t%v\n", ticker.Pair, ticker.Currency, ticker.CurrentAsk.Float64(), ticker.CurrentBid.Float64())
+		found++
+	}
+
+	writer.Flush()
+
+	if found == 0 {
+		return fail("pairs", errors.New("no pairs match the given filters"))
+	}
+
+	return exitOK
+}
@@ -0,0 +1,253 @@
+package main
+
+import (
+	"context"
+	"crypto-alert-bot/config"
+	"crypto-alert-bot/internal/adapters/api"
+	"crypto-alert-bot/internal/adapters/postgres"
+	"crypto-alert-bot/internal/adapters/prompt"
+	"crypto-alert-bot/internal/adapters/watchlist"
+	"crypto-alert-bot/internal/models"
+	"crypto-alert-bot/internal/services"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+var metricsInterval = time.Minute
+
+// stringList is a flag that can be repeated, collecting every value
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+// runCommand loads the tickers, either from a watchlist or the prompt, and runs a scheduler for each one
+func runCommand(args []string) int {
+	flags := flag.NewFlagSet("run", flag.ContinueOnError)
+	configPath := flags.String("config", "", "path to a YAML or JSON watchlist file, if empty the bot prompts for the tickers")
+	applyMigrations := flags.Bool("migrate", false, "apply the pending database migrations before starting")
+	rateLimit := flags.Int("rate-limit", models.DefaultRateLimit, "maximum calls per minute to the Uphold API, shared by every scheduler")
+	burst := flags.Int("burst", 10, "maximum calls to the Uphold API allowed at once before throttling")
+	retryAttempts := flags.Int("retry-attempts", services.DefaultRetryPolicy.MaxAttempts, "maximum attempts of a failed fetch, retrying only network errors, 5xx and 429")
+	retryDelay := flags.Duration("retry-delay", services.DefaultRetryPolicy.BaseDelay, "delay before the first retry, doubled on every attempt")
+	breakerFailures := flags.Int("breaker-failures", 5, "consecutive failed fetches that pause the polling of an exchange")
+	breakerCooldown := flags.Duration("breaker-cooldown", time.Minute, "how long the polling of a failing exchange is paused before probing it again")
+
+	var webhookURLs stringList
+	flags.Var(&webhookURLs, "webhook", "URL the alerts are posted to as JSON, can be repeated, signed with the WEBHOOK_SECRET environment variable")
+	publishTimeout := flags.Duration("publish-timeout", 10*time.Second, "how long an alert channel is waited for before moving on")
+
+	if err := flags.Parse(args); err != nil {
+		return parseFailure(err)
+	}
+
+	if *rateLimit <= 0 {
+		return fail("run", errors.New("-rate-limit must be positive"))
+	}
+
+	if *burst < 1 {
+		return fail("run", errors.New("-burst must be at least 1"))
+	}
+
+	rateLimits := models.RateLimits{api.DefaultExchange: *rateLimit}
+
+	loadDbConfigs := config.LoadDatabaseConfig()
+
+	db, err := config.ConnectToDatabase(loadDbConfigs)
+	if err != nil {
+		return fail("error on initializing db connection", err)
+	}
+	defer db.Close()
+
+	if *applyMigrations {
+		migrateCtx, migrateCancel := context.WithTimeout(context.Background(), migrateTimeout)
+		err = migrate(migrateCtx, db, loadDbConfigs)
+		migrateCancel()
+
+		if err != nil {
+			return fail("error applying migrations", err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	repo := postgres.NewPostgres(db, loadDbConfigs.Schema, loadDbConfigs.TableConfigs, loadDbConfigs.TableAlerts)
+
+	limiter := api.NewRateLimiter(*rateLimit, *burst)
+
+	upholdApi := api.NewUpholdApi(api.NewRateLimitedClient(limiter))
+
+	registry := api.NewDefaultRegistry(nil)
+	registry.Register(api.DefaultExchange, api.NewUpholdBatchApi(upholdApi))
+
+	var tickers *models.Tickers
+	var channels map[string]watchlist.Channel
+
+	if *configPath != "" {
+		file, err := watchlist.LoadFile(*configPath)
+		if err != nil {
+			return fail("error loading watchlist", err)
+		}
+
+		tickers, err = file.Build(registry, rateLimits)
+		if err != nil {
+			return fail("error loading watchlist", err)
+		}
+
+		channels = file.Channels
+	} else {
+		tickers = prompt.AskUserInput(upholdApi, rateLimits)
+	}
+
+	retryPolicy := services.DefaultRetryPolicy
+	retryPolicy.MaxAttempts = *retryAttempts
+	retryPolicy.BaseDelay = *retryDelay
+
+	breakers := services.NewCircuitBreakers(*breakerFailures, *breakerCooldown)
+
+	publisher, err := newFanOutPublisher(ctx, channels, webhookURLs, retryPolicy, repo, *publishTimeout)
+	if err != nil {
+		return fail("error creating alert channels", err)
+	}
+
+	for _, t := range *tickers {
+		for _, name := range t.Config.Channels {
+			if !publisher.Has(name) {
+				return fail("error creating alert channels", errors.Errorf("%s is routed to channel %q, which needs at least one -webhook", t.Pair, name))
+			}
+		}
+	}
+
+	coinbaseStream := api.NewCoinbaseStream()
+	streamers := map[string]services.QuoteStreamer{"coinbase": coinbaseStream}
+	streaming := false
+
+	for _, t := range *tickers {
+		if !t.Config.Stream {
+			continue
+		}
+
+		if _, ok := streamers[t.ExchangeName()]; !ok {
+			return fail("run", errors.Errorf("streaming is not supported for %s on %s, only on coinbase", t.Pair, t.ExchangeName()))
+		}
+
+		streaming = true
+	}
+
+	if streaming {
+		go coinbaseStream.Run(ctx)
+	}
+
+	var wg sync.WaitGroup
+
+	wg.Add(len(*tickers))
+
+	fmt.Println("Starting bot")
+
+	for _, t := range *tickers {
+		opts := []services.SchedulerOption{
+			services.WithRetryPolicy(retryPolicy),
+			services.WithCircuitBreaker(breakers.For(t.ExchangeName())),
+		}
+
+		if t.Config.Stream {
+			opts = append(opts, services.WithQuoteStream(streamers[t.ExchangeName()]))
+		}
+
+		go runSchedulerBot(ctx, &wg, *t, registry, repo, publisher, opts...)
+	}
+
+	go gracefulShutdown(cancel)
+
+	go logRateLimiterMetrics(ctx, limiter)
+
+	wg.Wait()
+
+	metrics := limiter.Metrics()
+	slog.Info("rate limiter metrics", "allowed", metrics.Allowed, "throttled", metrics.Throttled, "rate_limited", metrics.RateLimited)
+
+	return exitOK
+}
+
+func runSchedulerBot(ctx context.Context, wg *sync.WaitGroup, ticker models.Ticker, retriever services.DataRetriever, repo *postgres.Postgres, publisher services.Publisher, opts ...services.SchedulerOption) {
+	defer wg.Done()
+
+	tickerScheduler := services.NewTickerScheduler(retriever, &ticker, repo, publisher, opts...)
+
+	tickerScheduler.SchedulerStart(ctx)
+
+	if ticker.Config.Lifetime > 0 {
+		select {
+		case <-time.After(ticker.Config.Lifetime * time.Second):
+
+			tickerScheduler.SchedulerStop()
+
+			fmt.Printf("Scheduler for %s completed", ticker.Pair)
+		case <-ctx.Done():
+			fmt.Println("Shutting down scheduler for", ticker.Pair)
+
+			tickerScheduler.SchedulerStop()
+		}
+	} else {
+		select {
+		case <-ctx.Done():
+			fmt.Println("Shutting down scheduler for", ticker.Pair)
+
+			tickerScheduler.SchedulerStop()
+
+			return
+		}
+	}
+}
+
+// logRateLimiterMetrics periodically logs the rate limiter counters when calls were throttled since the last log
+func logRateLimiterMetrics(ctx context.Context, limiter *api.RateLimiter) {
+	timeTicker := time.NewTicker(metricsInterval)
+	defer timeTicker.Stop()
+
+	var last api.RateLimiterMetrics
+
+	for {
+		select {
+		case <-timeTicker.C:
+			metrics := limiter.Metrics()
+
+			if metrics.Throttled != last.Throttled || metrics.RateLimited != last.RateLimited {
+				slog.Warn("api calls throttled by the rate limiter", "allowed", metrics.Allowed,
+					"throttled", metrics.Throttled, "rate_limited", metrics.RateLimited)
+			}
+
+			last = metrics
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func gracefulShutdown(cancel context.CancelFunc) {
+	sigChan := make(chan os.Signal, 1)
+
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+
+	<-sigChan
+
+	fmt.Println("Shutting down...")
+
+	cancel()
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"crypto-alert-bot/internal/adapters/api"
+	"crypto-alert-bot/internal/adapters/watchlist"
+	"crypto-alert-bot/internal/models"
+	"flag"
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// validateCommand checks a watchlist against the API and the rate limit without starting the schedulers
+func validateCommand(args []string) int {
+	flags := flag.NewFlagSet("validate", flag.ContinueOnError)
+	configPath := flags.String("config", "", "path to the YAML or JSON watchlist file to validate (required)")
+	rateLimit := flags.Int("rate-limit", models.DefaultRateLimit, "maximum calls per minute to the Uphold API the bot runs with")
+
+	if err := flags.Parse(args); err != nil {
+		return parseFailure(err)
+	}
+
+	if *configPath == "" {
+		flags.Usage()
+		return fail("validate", errors.New("-config is required"))
+	}
+
+	if *rateLimit <= 0 {
+		return fail("validate", errors.New("-rate-limit must be positive"))
+	}
+
+	tickers, err := watchlist.Load(*configPath, api.NewDefaultRegistry(nil), models.RateLimits{api.DefaultExchange: *rateLimit})
+	if err != nil {
+		return fail("watchlist is not valid", err)
+	}
+
+	fmt.Printf("watchlist is valid, %d ticker(s):\n", len(*tickers))
+
+	for _, ticker := range *tickers {
+		window := "from the previous price"
+		if ticker.Config.Window > 0 {
+			window = fmt.Sprintf("within %v (%s baseline)", ticker.Config.Window, ticker.Config.WindowBaseline)
+		}
+
+		threshold := fmt.Sprintf("%v%% up / %v%% down", ticker.Config.Threshold(models.DirectionUp), ticker.Config.Threshold(models.DirectionDown))
+		if ticker.Config.ZScore > 0 {
+			threshold = fmt.Sprintf("%vσ of the returns within %v", ticker.Config.ZScore, ticker.Config.VolatilityWindow)
+		}
+
+		fmt.Printf("  %s on %s every %vs, %s threshold %s %s\n", ticker.Pair, ticker.ExchangeName(), ticker.Config.RefreshRate,
+			ticker.Config.Direction, threshold, window)
+
+		for _, rule := range ticker.Config.AlertRules() {
+			switch {
+			case rule.Type() == models.AlertIndicator && ticker.Config.IndicatorInterval > 0:
+				fmt.Printf("    rule: %s on %v bars\n", rule, ticker.Config.IndicatorInterval)
+			case rule.Type() != models.AlertThreshold:
+				fmt.Printf("    rule: %s\n", rule)
+			}
+		}
+	}
+
+	return exitOK
+}
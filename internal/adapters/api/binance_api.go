@@ -0,0 +1,74 @@
+package api
+
+import (
+	"context"
+	"crypto-alert-bot/internal/models"
+	"github.com/pkg/errors"
+	"net/http"
+	"net/url"
+)
+
+var BinanceURLTicker = "https://api.binance.com/api/v3/ticker/bookTicker"
+
+// BinanceApi represents the Binance public book ticker API
+type BinanceApi struct {
+	client *http.Client
+}
+
+// binanceResponse represents the Binance book ticker response
+type binanceResponse struct {
+	Symbol   string         `json:"symbol"`
+	AskPrice models.Float64 `json:"askPrice"`
+	BidPrice models.Float64 `json:"bidPrice"`
+}
+
+// NewBinanceApi returns a new instance of BinanceApi
+func NewBinanceApi(client *http.Client) *BinanceApi {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return &BinanceApi{
+		client: client,
+	}
+}
+
+// FetchPairData fetches the data for a given pair
+func (a *BinanceApi) FetchPairData(ctx context.Context, ticker *models.Ticker) error {
+	var response binanceResponse
+
+	_, err := getJSON(ctx, a.client, binanceTickerURL(ticker.Pair), &response)
+	if err != nil {
+		return err
+	}
+
+	ticker.CurrentAsk = response.AskPrice
+	ticker.CurrentBid = response.BidPrice
+
+	return nil
+}
+
+// IsPairValid checks if the pair exists on Binance, which answers unknown symbols with a bad request
+func (a *BinanceApi) IsPairValid(pair string) (bool, error) {
+	var response binanceResponse
+
+	status, err := getJSON(context.Background(), a.client, binanceTickerURL(pair), &response)
+	if status == http.StatusBadRequest || status == http.StatusNotFound {
+		return false, errors.Errorf("pair doesn't exist, try again")
+	}
+
+	if err != nil {
+		return false, errors.Wrap(err, "can't validate pair")
+	}
+
+	return true, nil
+}
+
+// binanceTickerURL returns the ticker URL for the pair in the Binance notation
+func binanceTickerURL(pair string) string {
+	symbol := exchangeSymbol(pair, func(base, quote string) string {
+		return base + quote
+	})
+
+	return BinanceURLTicker + "?symbol=" + url.QueryEscape(symbol)
+}
@@ -0,0 +1,108 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"crypto-alert-bot/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBinanceFetchPairData(t *testing.T) {
+	tests := []struct {
+		name         string
+		pair         string
+		statusCode   int
+		responseBody string
+		wantSymbol   string
+		wantErr      bool
+		errContains  string
+	}{
+		{
+			name:         "Success - normalized pair",
+			pair:         "BTC-USDT",
+			statusCode:   http.StatusOK,
+			responseBody: `{"symbol":"BTCUSDT","bidPrice":"120.00","bidQty":"1","askPrice":"123.45","askQty":"1"}`,
+			wantSymbol:   "BTCUSDT",
+		},
+		{
+			name:         "Error - invalid symbol (400)",
+			pair:         "FAKE-USDT",
+			statusCode:   http.StatusBadRequest,
+			responseBody: `{"code":-1121,"msg":"Invalid symbol."}`,
+			wantSymbol:   "FAKEUSDT",
+			wantErr:      true,
+			errContains:  "unexpected status code: 400",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, tt.wantSymbol, r.URL.Query().Get("symbol"))
+				w.WriteHeader(tt.statusCode)
+				_, _ = w.Write([]byte(tt.responseBody))
+			}))
+			defer server.Close()
+
+			oldURL := BinanceURLTicker
+			BinanceURLTicker = server.URL
+			defer func() { BinanceURLTicker = oldURL }()
+
+			ticker := &models.Ticker{Pair: tt.pair}
+
+			err := NewBinanceApi(nil).FetchPairData(context.Background(), ticker)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errContains)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, 123.45, ticker.CurrentAsk.Float64())
+			assert.Equal(t, 120.00, ticker.CurrentBid.Float64())
+		})
+	}
+}
+
+func TestBinanceIsPairValid(t *testing.T) {
+	tests := []struct {
+		name        string
+		statusCode  int
+		wantValid   bool
+		errContains string
+	}{
+		{name: "Valid (200)", statusCode: http.StatusOK, wantValid: true},
+		{name: "Invalid symbol (400)", statusCode: http.StatusBadRequest, errContains: "pair doesn't exist"},
+		{name: "Unexpected status code (503)", statusCode: http.StatusServiceUnavailable, errContains: "unexpected status code: 503"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.statusCode)
+				_, _ = w.Write([]byte(`{"askPrice":"1","bidPrice":"1"}`))
+			}))
+			defer server.Close()
+
+			oldURL := BinanceURLTicker
+			BinanceURLTicker = server.URL
+			defer func() { BinanceURLTicker = oldURL }()
+
+			valid, err := NewBinanceApi(nil).IsPairValid("BTC-USDT")
+
+			assert.Equal(t, tt.wantValid, valid)
+			if tt.errContains != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errContains)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package api
+
+import (
+	"context"
+	"crypto-alert-bot/internal/models"
+	"fmt"
+	"github.com/pkg/errors"
+	"net/http"
+)
+
+var CoinbaseURLProducts = "https://api.exchange.coinbase.com/products"
+
+// CoinbaseApi represents the Coinbase Exchange public ticker API
+type CoinbaseApi struct {
+	client *http.Client
+}
+
+// coinbaseResponse represents the Coinbase product ticker response
+type coinbaseResponse struct {
+	Ask   models.Float64 `json:"ask"`
+	Bid   models.Float64 `json:"bid"`
+	Price models.Float64 `json:"price"`
+}
+
+// NewCoinbaseApi returns a new instance of CoinbaseApi
+func NewCoinbaseApi(client *http.Client) *CoinbaseApi {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return &CoinbaseApi{
+		client: client,
+	}
+}
+
+// FetchPairData fetches the data for a given pair
+func (a *CoinbaseApi) FetchPairData(ctx context.Context, ticker *models.Ticker) error {
+	var response coinbaseResponse
+
+	_, err := getJSON(ctx, a.client, coinbaseTickerURL(ticker.Pair), &response)
+	if err != nil {
+		return err
+	}
+
+	ticker.CurrentAsk = response.Ask
+	ticker.CurrentBid = response.Bid
+	ticker.CurrentLast = response.Price
+
+	return nil
+}
+
+// HasLastPrice reports that Coinbase provides the last trade price
+func (a *CoinbaseApi) HasLastPrice() bool {
+	return true
+}
+
+// IsPairValid checks if the pair exists on Coinbase
+func (a *CoinbaseApi) IsPairValid(pair string) (bool, error) {
+	var response coinbaseResponse
+
+	status, err := getJSON(context.Background(), a.client, coinbaseTickerURL(pair), &response)
+	if status == http.StatusNotFound || status == http.StatusBadRequest {
+		return false, errors.Errorf("pair doesn't exist, try again")
+	}
+
+	if err != nil {
+		return false, errors.Wrap(err, "can't validate pair")
+	}
+
+	return true, nil
+}
+
+// coinbaseTickerURL returns the ticker URL for the pair in the Coinbase notation
+func coinbaseTickerURL(pair string) string {
+	return fmt.Sprintf("%s/%s/ticker", CoinbaseURLProducts, coinbaseProduct(pair))
+}
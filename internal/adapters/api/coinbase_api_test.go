@@ -0,0 +1,118 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"crypto-alert-bot/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCoinbaseFetchPairData(t *testing.T) {
+	tests := []struct {
+		name         string
+		pair         string
+		statusCode   int
+		responseBody string
+		wantPath     string
+		wantErr      bool
+		errContains  string
+	}{
+		{
+			name:         "Success - normalized pair",
+			pair:         "btc/usd",
+			statusCode:   http.StatusOK,
+			responseBody: `{"ask":"123.45","bid":"120.00","price":"121.00"}`,
+			wantPath:     "/BTC-USD/ticker",
+		},
+		{
+			name:         "Error - non-OK status code (404)",
+			pair:         "FAKE-USD",
+			statusCode:   http.StatusNotFound,
+			responseBody: `{"message":"NotFound"}`,
+			wantPath:     "/FAKE-USD/ticker",
+			wantErr:      true,
+			errContains:  "unexpected status code: 404",
+		},
+		{
+			name:         "Error - invalid JSON",
+			pair:         "BTC-USD",
+			statusCode:   http.StatusOK,
+			responseBody: `{"ask":"badjson"`,
+			wantPath:     "/BTC-USD/ticker",
+			wantErr:      true,
+			errContains:  "error unmarshalling api response",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, tt.wantPath, r.URL.Path)
+				w.WriteHeader(tt.statusCode)
+				_, _ = w.Write([]byte(tt.responseBody))
+			}))
+			defer server.Close()
+
+			oldURL := CoinbaseURLProducts
+			CoinbaseURLProducts = server.URL
+			defer func() { CoinbaseURLProducts = oldURL }()
+
+			ticker := &models.Ticker{Pair: tt.pair}
+
+			err := NewCoinbaseApi(nil).FetchPairData(context.Background(), ticker)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errContains)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, 123.45, ticker.CurrentAsk.Float64())
+			assert.Equal(t, 120.00, ticker.CurrentBid.Float64())
+			assert.Equal(t, 121.00, ticker.CurrentLast.Float64())
+		})
+	}
+}
+
+func TestCoinbaseIsPairValid(t *testing.T) {
+	tests := []struct {
+		name        string
+		statusCode  int
+		wantValid   bool
+		errContains string
+	}{
+		{name: "Valid (200)", statusCode: http.StatusOK, wantValid: true},
+		{name: "Pair not found (404)", statusCode: http.StatusNotFound, errContains: "pair doesn't exist"},
+		{name: "Unexpected status code (500)", statusCode: http.StatusInternalServerError, errContains: "unexpected status code: 500"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.statusCode)
+				_, _ = w.Write([]byte(`{"ask":"1","bid":"1"}`))
+			}))
+			defer server.Close()
+
+			oldURL := CoinbaseURLProducts
+			CoinbaseURLProducts = server.URL
+			defer func() { CoinbaseURLProducts = oldURL }()
+
+			valid, err := NewCoinbaseApi(nil).IsPairValid("BTC-USD")
+
+			assert.Equal(t, tt.wantValid, valid)
+			if tt.errContains != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errContains)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
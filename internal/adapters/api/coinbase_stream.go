@@ -0,0 +1,231 @@
+package api
+
+import (
+	"context"
+	"crypto-alert-bot/internal/models"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/pkg/errors"
+)
+
+var CoinbaseURLStream = "wss://ws-feed.exchange.coinbase.com"
+
+// streamReconnectDelay is the first wait before reconnecting a dropped stream, doubled up to streamMaxReconnectDelay
+var streamReconnectDelay = time.Second
+var streamMaxReconnectDelay = 30 * time.Second
+
+// streamReadTimeout closes connections that stop sending messages, the heartbeat channel sends one every second
+var streamReadTimeout = 30 * time.Second
+
+// CoinbaseStream represents the Coinbase Exchange ticker WebSocket feed, shared by every streamed ticker
+type CoinbaseStream struct {
+	mu          sync.Mutex
+	conn        *websocket.Conn
+	subscribers map[string][]chan models.Quote
+}
+
+// coinbaseSubscription represents the subscribe message sent to the feed
+type coinbaseSubscription struct {
+	Type       string   `json:"type"`
+	ProductIDs []string `json:"product_ids"`
+	Channels   []string `json:"channels"`
+}
+
+// coinbaseMessage represents the feed messages, only ticker and error messages are handled
+type coinbaseMessage struct {
+	Type      string         `json:"type"`
+	ProductID string         `json:"product_id"`
+	BestAsk   models.Float64 `json:"best_ask"`
+	BestBid   models.Float64 `json:"best_bid"`
+	Price     models.Float64 `json:"price"`
+	Time      time.Time      `json:"time"`
+	Message   string         `json:"message"`
+	Reason    string         `json:"reason"`
+}
+
+// NewCoinbaseStream returns a new instance of CoinbaseStream
+func NewCoinbaseStream() *CoinbaseStream {
+	return &CoinbaseStream{
+		subscribers: make(map[string][]chan models.Quote),
+	}
+}
+
+// Subscribe registers the ticker on the feed and returns the channel its quotes are pushed to.
+// When the channel consumer falls behind only the latest quote is kept. The channel is closed once the context is done
+func (s *CoinbaseStream) Subscribe(ctx context.Context, ticker *models.Ticker) (<-chan models.Quote, error) {
+	product := coinbaseProduct(ticker.Pair)
+	quotes := make(chan models.Quote, 1)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.subscribers[product] = append(s.subscribers[product], quotes)
+
+	if s.conn != nil {
+		if err := s.conn.WriteJSON(newCoinbaseSubscription(product)); err != nil {
+			slog.Warn("error subscribing to coinbase stream, retrying on reconnect", "pair", ticker.Pair, "error", err)
+		}
+	}
+
+	go func() {
+		<-ctx.Done()
+		s.unsubscribe(product, quotes)
+	}()
+
+	return quotes, nil
+}
+
+// Run keeps the feed connected until the context is done, reconnecting with backoff and resubscribing every pair after a drop
+func (s *CoinbaseStream) Run(ctx context.Context) {
+	delay := streamReconnectDelay
+
+	for {
+		connected, err := s.connectAndRead(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+
+		if connected {
+			delay = streamReconnectDelay
+		}
+
+		slog.Warn("coinbase stream dropped, reconnecting", "error", err, "delay", delay)
+
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return
+		}
+
+		delay *= 2
+		if delay > streamMaxReconnectDelay {
+			delay = streamMaxReconnectDelay
+		}
+	}
+}
+
+// connectAndRead dials the feed, subscribes every registered pair and dispatches the quotes until the connection drops
+func (s *CoinbaseStream) connectAndRead(ctx context.Context) (bool, error) {
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, CoinbaseURLStream, nil)
+	if err != nil {
+		return false, errors.Wrap(err, "error connecting to coinbase stream")
+	}
+	defer conn.Close()
+
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	err = s.attach(conn)
+	if err != nil {
+		return false, err
+	}
+	defer s.detach()
+
+	for {
+		var message coinbaseMessage
+
+		conn.SetReadDeadline(time.Now().Add(streamReadTimeout))
+
+		if err = conn.ReadJSON(&message); err != nil {
+			return true, errors.Wrap(err, "error reading coinbase stream")
+		}
+
+		switch message.Type {
+		case "ticker":
+			if message.Time.IsZero() {
+				message.Time = time.Now().UTC()
+			}
+
+			s.dispatch(message.ProductID, models.Quote{Ask: message.BestAsk, Bid: message.BestBid, Last: message.Price, Time: message.Time})
+		case "error":
+			slog.Error("coinbase stream error", "message", message.Message, "reason", message.Reason)
+		}
+	}
+}
+
+// attach sets the connection as the current one and subscribes every registered pair
+func (s *CoinbaseStream) attach(conn *websocket.Conn) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	products := make([]string, 0, len(s.subscribers))
+	for product := range s.subscribers {
+		products = append(products, product)
+	}
+
+	if len(products) > 0 {
+		if err := conn.WriteJSON(newCoinbaseSubscription(products...)); err != nil {
+			return errors.Wrap(err, "error subscribing to coinbase stream")
+		}
+	}
+
+	s.conn = conn
+
+	return nil
+}
+
+// detach clears the current connection
+func (s *CoinbaseStream) detach() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.conn = nil
+}
+
+// dispatch pushes the quote to every subscriber of the product, replacing a quote not yet consumed
+func (s *CoinbaseStream) dispatch(product string, quote models.Quote) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, quotes := range s.subscribers[product] {
+		select {
+		case quotes <- quote:
+		default:
+			select {
+			case <-quotes:
+			default:
+			}
+			quotes <- quote
+		}
+	}
+}
+
+// unsubscribe removes and closes the subscriber channel
+func (s *CoinbaseStream) unsubscribe(product string, quotes chan models.Quote) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	subscribers := s.subscribers[product]
+
+	for i, subscriber := range subscribers {
+		if subscriber == quotes {
+			s.subscribers[product] = append(subscribers[:i], subscribers[i+1:]...)
+			break
+		}
+	}
+
+	if len(s.subscribers[product]) == 0 {
+		delete(s.subscribers, product)
+	}
+
+	close(quotes)
+}
+
+// newCoinbaseSubscription returns the subscribe message for the products
+func newCoinbaseSubscription(products ...string) coinbaseSubscription {
+	return coinbaseSubscription{
+		Type:       "subscribe",
+		ProductIDs: products,
+		Channels:   []string{"ticker", "heartbeat"},
+	}
+}
+
+// coinbaseProduct returns the pair in the Coinbase notation
+func coinbaseProduct(pair string) string {
+	return exchangeSymbol(pair, func(base, quote string) string {
+		return base + "-" + quote
+	})
+}
@@ -0,0 +1,109 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"crypto-alert-bot/internal/models"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCoinbaseStream(t *testing.T) {
+	var connections atomic.Int32
+	subscriptions := make(chan coinbaseSubscription, 10)
+	firstReceived := make(chan struct{})
+
+	upgrader := websocket.Upgrader{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		connection := connections.Add(1)
+
+		var subscription coinbaseSubscription
+		if err = conn.ReadJSON(&subscription); err != nil {
+			return
+		}
+		subscriptions <- subscription
+
+		ask := "100.5"
+		if connection > 1 {
+			ask = "200.5"
+		}
+
+		_ = conn.WriteJSON(map[string]string{"type": "subscriptions"})
+		_ = conn.WriteJSON(map[string]string{"type": "ticker", "product_id": "ETH-USD", "best_ask": "1", "best_bid": "1"})
+		_ = conn.WriteJSON(map[string]string{"type": "ticker", "product_id": "BTC-USD", "best_ask": ask, "best_bid": "99.5"})
+
+		if connection == 1 {
+			<-firstReceived
+			return
+		}
+
+		_, _, _ = conn.ReadMessage()
+	}))
+	defer server.Close()
+
+	oldURL, oldDelay := CoinbaseURLStream, streamReconnectDelay
+	CoinbaseURLStream = "ws" + strings.TrimPrefix(server.URL, "http")
+	streamReconnectDelay = 10 * time.Millisecond
+	defer func() { CoinbaseURLStream, streamReconnectDelay = oldURL, oldDelay }()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream := NewCoinbaseStream()
+
+	subCtx, subCancel := context.WithCancel(ctx)
+	quotes, err := stream.Subscribe(subCtx, &models.Ticker{Pair: "btc-usd", Exchange: "coinbase"})
+	assert.NoError(t, err)
+
+	go stream.Run(ctx)
+
+	first := receiveQuote(t, quotes)
+	assert.Equal(t, 100.5, first.Ask.Float64())
+	assert.Equal(t, 99.5, first.Bid.Float64())
+	close(firstReceived)
+
+	second := receiveQuote(t, quotes)
+	assert.Equal(t, 200.5, second.Ask.Float64(), "quotes are received again after reconnecting")
+
+	assert.Equal(t, int32(2), connections.Load())
+	for i := 0; i < 2; i++ {
+		subscription := <-subscriptions
+		assert.Equal(t, "subscribe", subscription.Type)
+		assert.Equal(t, []string{"BTC-USD"}, subscription.ProductIDs, "pairs are resubscribed after reconnecting")
+	}
+
+	subCancel()
+
+	select {
+	case _, ok := <-quotes:
+		assert.False(t, ok, "channel is closed once the subscription context is done")
+	case <-time.After(time.Second):
+		t.Fatal("subscription channel was not closed")
+	}
+}
+
+// receiveQuote waits for the next quote of the subscription
+func receiveQuote(t *testing.T, quotes <-chan models.Quote) models.Quote {
+	t.Helper()
+
+	select {
+	case quote := <-quotes:
+		return quote
+	case <-time.After(2 * time.Second):
+		t.Fatal("no quote received")
+		return models.Quote{}
+	}
+}
@@ -0,0 +1,67 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/pkg/errors"
+	"io"
+	"net/http"
+	"time"
+)
+
+// StatusError is returned when an exchange answers with an unexpected status code
+type StatusError struct {
+	StatusCode int
+	RetryAfter time.Duration
+}
+
+// newStatusError builds the StatusError for the response, reading the Retry-After header of 429 responses
+func newStatusError(resp *http.Response) *StatusError {
+	statusErr := &StatusError{StatusCode: resp.StatusCode}
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		statusErr.RetryAfter = parseRetryAfter(resp.Header.Get("Retry-After"))
+	}
+
+	return statusErr
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unexpected status code: %d", e.StatusCode)
+}
+
+// Retryable checks if the request can be retried, which is the case for server errors and rate limited requests
+func (e *StatusError) Retryable() bool {
+	return e.StatusCode >= http.StatusInternalServerError || e.StatusCode == http.StatusTooManyRequests
+}
+
+// getJSON sends a GET request and decodes a successful JSON response into dest, returning the response status code
+func getJSON(ctx context.Context, client *http.Client, url string, dest any) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return 0, errors.Wrap(err, "error creating api request")
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return resp.StatusCode, newStatusError(resp)
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return resp.StatusCode, errors.Wrap(err, "error reading api response")
+	}
+
+	err = json.Unmarshal(respBody, dest)
+	if err != nil {
+		return resp.StatusCode, errors.Wrap(err, "error unmarshalling api response")
+	}
+
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,121 @@
+package api
+
+import (
+	"context"
+	"crypto-alert-bot/internal/models"
+	"github.com/pkg/errors"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+var KrakenURLTicker = "https://api.kraken.com/0/public/Ticker"
+
+// krakenAssets maps the normalized asset codes to the ones used by Kraken
+var krakenAssets = map[string]string{
+	"BTC":  "XBT",
+	"DOGE": "XDG",
+}
+
+// KrakenApi represents the Kraken public ticker API
+type KrakenApi struct {
+	client *http.Client
+}
+
+// krakenResponse represents the Kraken ticker response, where a is [price, whole lot volume, lot volume]
+type krakenResponse struct {
+	Error  []string `json:"error"`
+	Result map[string]struct {
+		Ask  []models.Float64 `json:"a"`
+		Bid  []models.Float64 `json:"b"`
+		Last []models.Float64 `json:"c"`
+	} `json:"result"`
+}
+
+// NewKrakenApi returns a new instance of KrakenApi
+func NewKrakenApi(client *http.Client) *KrakenApi {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return &KrakenApi{
+		client: client,
+	}
+}
+
+// FetchPairData fetches the data for a given pair
+func (a *KrakenApi) FetchPairData(ctx context.Context, ticker *models.Ticker) error {
+	response, err := a.fetch(ctx, ticker.Pair)
+	if err != nil {
+		return err
+	}
+
+	if len(response.Error) > 0 {
+		return errors.Errorf("kraken api error: %s", strings.Join(response.Error, ", "))
+	}
+
+	for _, data := range response.Result {
+		if len(data.Ask) == 0 || len(data.Bid) == 0 {
+			return errors.New("error parsing API response: missing ask or bid")
+		}
+
+		ticker.CurrentAsk = data.Ask[0]
+		ticker.CurrentBid = data.Bid[0]
+
+		if len(data.Last) > 0 {
+			ticker.CurrentLast = data.Last[0]
+		}
+
+		return nil
+	}
+
+	return errors.Errorf("kraken api returned no data for %s", ticker.Pair)
+}
+
+// HasLastPrice reports that Kraken provides the last trade price
+func (a *KrakenApi) HasLastPrice() bool {
+	return true
+}
+
+// IsPairValid checks if the pair exists on Kraken
+func (a *KrakenApi) IsPairValid(pair string) (bool, error) {
+	response, err := a.fetch(context.Background(), pair)
+	if err != nil {
+		return false, errors.Wrap(err, "can't validate pair")
+	}
+
+	if len(response.Error) > 0 || len(response.Result) == 0 {
+		return false, errors.Errorf("pair doesn't exist, try again")
+	}
+
+	if len(response.Result) > 1 {
+		return false, errors.Errorf("cant use %s because it returns more than one pair. Please specify a ticker for a single pair", pair)
+	}
+
+	return true, nil
+}
+
+// fetch requests the ticker for the pair in the Kraken notation
+func (a *KrakenApi) fetch(ctx context.Context, pair string) (*krakenResponse, error) {
+	symbol := exchangeSymbol(pair, func(base, quote string) string {
+		return krakenAsset(base) + krakenAsset(quote)
+	})
+
+	var response krakenResponse
+
+	_, err := getJSON(ctx, a.client, KrakenURLTicker+"?pair="+url.QueryEscape(symbol), &response)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+// krakenAsset returns the Kraken code for the asset
+func krakenAsset(asset string) string {
+	if code, ok := krakenAssets[asset]; ok {
+		return code
+	}
+
+	return asset
+}
@@ -0,0 +1,137 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"crypto-alert-bot/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKrakenFetchPairData(t *testing.T) {
+	tests := []struct {
+		name         string
+		pair         string
+		statusCode   int
+		responseBody string
+		wantSymbol   string
+		wantLast     float64
+		wantErr      bool
+		errContains  string
+	}{
+		{
+			name:         "Success - normalized pair is converted to the Kraken notation",
+			pair:         "BTC-USD",
+			statusCode:   http.StatusOK,
+			responseBody: `{"error":[],"result":{"XXBTZUSD":{"a":["123.45","1","1.000"],"b":["120.00","2","2.000"],"c":["121.00","0.1"]}}}`,
+			wantSymbol:   "XBTUSD",
+			wantLast:     121.00,
+		},
+		{
+			name:         "Success - pair in the Kraken notation is kept",
+			pair:         "XBTEUR",
+			statusCode:   http.StatusOK,
+			responseBody: `{"error":[],"result":{"XXBTZEUR":{"a":["123.45","1","1.000"],"b":["120.00","2","2.000"]}}}`,
+			wantSymbol:   "XBTEUR",
+		},
+		{
+			name:         "Error - kraken error list",
+			pair:         "FAKE-USD",
+			statusCode:   http.StatusOK,
+			responseBody: `{"error":["EQuery:Unknown asset pair"]}`,
+			wantSymbol:   "FAKEUSD",
+			wantErr:      true,
+			errContains:  "EQuery:Unknown asset pair",
+		},
+		{
+			name:        "Error - non-OK status code (502)",
+			pair:        "BTC-USD",
+			statusCode:  http.StatusBadGateway,
+			wantSymbol:  "XBTUSD",
+			wantErr:     true,
+			errContains: "unexpected status code: 502",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, tt.wantSymbol, r.URL.Query().Get("pair"))
+				w.WriteHeader(tt.statusCode)
+				_, _ = w.Write([]byte(tt.responseBody))
+			}))
+			defer server.Close()
+
+			oldURL := KrakenURLTicker
+			KrakenURLTicker = server.URL
+			defer func() { KrakenURLTicker = oldURL }()
+
+			ticker := &models.Ticker{Pair: tt.pair}
+
+			err := NewKrakenApi(nil).FetchPairData(context.Background(), ticker)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errContains)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, 123.45, ticker.CurrentAsk.Float64())
+			assert.Equal(t, 120.00, ticker.CurrentBid.Float64())
+			assert.Equal(t, tt.wantLast, ticker.CurrentLast.Float64())
+		})
+	}
+}
+
+func TestKrakenIsPairValid(t *testing.T) {
+	tests := []struct {
+		name         string
+		responseBody string
+		wantValid    bool
+		errContains  string
+	}{
+		{
+			name:         "Valid pair",
+			responseBody: `{"error":[],"result":{"XXBTZUSD":{"a":["1","1","1"],"b":["1","1","1"]}}}`,
+			wantValid:    true,
+		},
+		{
+			name:         "Unknown pair",
+			responseBody: `{"error":["EQuery:Unknown asset pair"]}`,
+			errContains:  "pair doesn't exist",
+		},
+		{
+			name:         "Multiple pairs returned",
+			responseBody: `{"error":[],"result":{"XXBTZUSD":{},"XXBTZEUR":{}}}`,
+			errContains:  "returns more than one pair",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(tt.responseBody))
+			}))
+			defer server.Close()
+
+			oldURL := KrakenURLTicker
+			KrakenURLTicker = server.URL
+			defer func() { KrakenURLTicker = oldURL }()
+
+			valid, err := NewKrakenApi(nil).IsPairValid("BTC-USD")
+
+			assert.Equal(t, tt.wantValid, valid)
+			if tt.errContains != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errContains)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
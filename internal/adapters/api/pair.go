@@ -0,0 +1,17 @@
+package api
+
+import (
+	"crypto-alert-bot/internal/models"
+	"strings"
+)
+
+// exchangeSymbol converts a pair in the normalized notation to the exchange notation,
+// pairs already written in the exchange notation are kept as they are
+func exchangeSymbol(pair string, format func(base, quote string) string) string {
+	base, quote, err := models.ParsePair(pair)
+	if err != nil {
+		return strings.ToUpper(strings.TrimSpace(pair))
+	}
+
+	return format(base, quote)
+}
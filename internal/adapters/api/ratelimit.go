@@ -0,0 +1,173 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// defaultRetryAfter is the pause applied when a 429 response has no valid Retry-After header
+var defaultRetryAfter = 5 * time.Second
+
+// RateLimiter is a token bucket shared by every request sent to an exchange,
+// refilled at the configured calls per minute and holding up to burst tokens
+type RateLimiter struct {
+	mu           sync.Mutex
+	rate         float64
+	burst        float64
+	tokens       float64
+	last         time.Time
+	blockedUntil time.Time
+	allowed      atomic.Int64
+	throttled    atomic.Int64
+	rateLimited  atomic.Int64
+}
+
+// RateLimiterMetrics holds the counters of a RateLimiter
+type RateLimiterMetrics struct {
+	Allowed     int64
+	Throttled   int64
+	RateLimited int64
+}
+
+// NewRateLimiter returns a new instance of RateLimiter allowing callsPerMinute with bursts of up to burst calls
+func NewRateLimiter(callsPerMinute, burst int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &RateLimiter{
+		rate:   float64(callsPerMinute) / 60,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// Wait blocks until a call is allowed or the context is done
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	throttled := false
+
+	for {
+		wait := l.reserve()
+		if wait <= 0 {
+			l.allowed.Add(1)
+			return nil
+		}
+
+		if !throttled {
+			throttled = true
+			l.throttled.Add(1)
+		}
+
+		timer := time.NewTimer(wait)
+
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+// reserve takes a token if one is available, otherwise returns how long to wait for the next one
+func (l *RateLimiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+
+	if now.Before(l.blockedUntil) {
+		return l.blockedUntil.Sub(now)
+	}
+
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+
+	if l.tokens >= 1 {
+		l.tokens--
+		return 0
+	}
+
+	return time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+}
+
+// Block stops every call until the given time and empties the bucket, used when the exchange answers with a 429
+func (l *RateLimiter) Block(until time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if until.After(l.blockedUntil) {
+		l.blockedUntil = until
+	}
+
+	l.tokens = 0
+	l.last = until
+}
+
+// Metrics returns a snapshot of the limiter counters
+func (l *RateLimiter) Metrics() RateLimiterMetrics {
+	return RateLimiterMetrics{
+		Allowed:     l.allowed.Load(),
+		Throttled:   l.throttled.Load(),
+		RateLimited: l.rateLimited.Load(),
+	}
+}
+
+// RateLimitedTransport is an http.RoundTripper that waits on the RateLimiter before each request
+// and pauses the limiter for the Retry-After duration of 429 responses
+type RateLimitedTransport struct {
+	next    http.RoundTripper
+	limiter *RateLimiter
+}
+
+// NewRateLimitedClient returns an http.Client whose requests go through the limiter
+func NewRateLimitedClient(limiter *RateLimiter) *http.Client {
+	return &http.Client{
+		Transport: &RateLimitedTransport{
+			next:    http.DefaultTransport,
+			limiter: limiter,
+		},
+	}
+}
+
+// RoundTrip sends the request once the limiter allows it
+func (t *RateLimitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.limiter.Wait(req.Context()); err != nil {
+		return nil, err
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		t.limiter.rateLimited.Add(1)
+		t.limiter.Block(time.Now().Add(parseRetryAfter(resp.Header.Get("Retry-After"))))
+	}
+
+	return resp, nil
+}
+
+// parseRetryAfter reads a Retry-After header, either in seconds or as an HTTP date
+func parseRetryAfter(value string) time.Duration {
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		if wait := time.Until(date); wait > 0 {
+			return wait
+		}
+	}
+
+	return defaultRetryAfter
+}
@@ -0,0 +1,90 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimiterWait(t *testing.T) {
+	t.Run("Burst calls are allowed immediately", func(t *testing.T) {
+		limiter := NewRateLimiter(60, 3)
+
+		start := time.Now()
+		for i := 0; i < 3; i++ {
+			assert.NoError(t, limiter.Wait(context.Background()))
+		}
+
+		assert.Less(t, time.Since(start), 50*time.Millisecond)
+		assert.Equal(t, RateLimiterMetrics{Allowed: 3}, limiter.Metrics())
+	})
+
+	t.Run("Calls above the burst are throttled to the rate", func(t *testing.T) {
+		limiter := NewRateLimiter(600, 1)
+
+		start := time.Now()
+		for i := 0; i < 3; i++ {
+			assert.NoError(t, limiter.Wait(context.Background()))
+		}
+
+		assert.GreaterOrEqual(t, time.Since(start), 180*time.Millisecond)
+		assert.Equal(t, int64(2), limiter.Metrics().Throttled)
+	})
+
+	t.Run("Context is honoured while throttled", func(t *testing.T) {
+		limiter := NewRateLimiter(1, 1)
+		assert.NoError(t, limiter.Wait(context.Background()))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		assert.ErrorIs(t, limiter.Wait(ctx), context.DeadlineExceeded)
+	})
+}
+
+func TestRateLimitedTransport(t *testing.T) {
+	var calls atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	limiter := NewRateLimiter(6000, 10)
+	client := NewRateLimitedClient(limiter)
+
+	resp, err := client.Get(server.URL)
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+
+	start := time.Now()
+
+	resp, err = client.Get(server.URL)
+	assert.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.GreaterOrEqual(t, time.Since(start), 900*time.Millisecond)
+	assert.Equal(t, RateLimiterMetrics{Allowed: 2, Throttled: 1, RateLimited: 1}, limiter.Metrics())
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	assert.Equal(t, 3*time.Second, parseRetryAfter("3"))
+	assert.Equal(t, defaultRetryAfter, parseRetryAfter(""))
+	assert.Equal(t, defaultRetryAfter, parseRetryAfter("soon"))
+
+	wait := parseRetryAfter(time.Now().Add(10 * time.Second).UTC().Format(http.TimeFormat))
+	assert.InDelta(t, 10*time.Second, wait, float64(2*time.Second))
+}
@@ -0,0 +1,138 @@
+package api
+
+import (
+	"context"
+	"crypto-alert-bot/internal/models"
+	"github.com/pkg/errors"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// DefaultExchange is the exchange used by tickers that don't specify one
+const DefaultExchange = models.DefaultExchange
+
+// Provider represents an exchange able to fetch and validate pairs
+type Provider interface {
+	FetchPairData(context.Context, *models.Ticker) error
+	IsPairValid(pair string) (bool, error)
+}
+
+// LastPriceProvider is implemented by the providers that fetch the last trade price of a pair
+type LastPriceProvider interface {
+	HasLastPrice() bool
+}
+
+// CurrencyProvider is implemented by the providers knowing the currency the pairs they validated are quoted in
+type CurrencyProvider interface {
+	PairCurrency(pair string) string
+}
+
+// Registry holds the providers keyed by exchange name and routes each ticker to its exchange
+type Registry struct {
+	providers map[string]Provider
+}
+
+// NewRegistry returns a new empty instance of Registry
+func NewRegistry() *Registry {
+	return &Registry{
+		providers: make(map[string]Provider),
+	}
+}
+
+// NewDefaultRegistry returns a Registry with every supported exchange registered
+func NewDefaultRegistry(client *http.Client) *Registry {
+	registry := NewRegistry()
+
+	registry.Register(DefaultExchange, NewUpholdBatchApi(NewUpholdApi(client)))
+	registry.Register("kraken", NewKrakenApi(client))
+	registry.Register("coinbase", NewCoinbaseApi(client))
+	registry.Register("binance", NewBinanceApi(client))
+
+	return registry
+}
+
+// Register adds or replaces the provider for the exchange
+func (r *Registry) Register(exchange string, provider Provider) {
+	r.providers[normalizeExchange(exchange)] = provider
+}
+
+// Provider returns the provider registered for the exchange, an empty name resolves to the default exchange
+func (r *Registry) Provider(exchange string) (Provider, error) {
+	provider, ok := r.providers[normalizeExchange(exchange)]
+	if !ok {
+		return nil, errors.Errorf("unknown exchange %q, supported exchanges are %s", exchange, strings.Join(r.Exchanges(), ", "))
+	}
+
+	return provider, nil
+}
+
+// Exchanges returns the names of the registered exchanges, sorted
+func (r *Registry) Exchanges() []string {
+	exchanges := make([]string, 0, len(r.providers))
+
+	for exchange := range r.providers {
+		exchanges = append(exchanges, exchange)
+	}
+
+	sort.Strings(exchanges)
+
+	return exchanges
+}
+
+// FetchPairData fetches the data for a given pair from the ticker exchange
+func (r *Registry) FetchPairData(ctx context.Context, ticker *models.Ticker) error {
+	provider, err := r.Provider(ticker.Exchange)
+	if err != nil {
+		return err
+	}
+
+	return provider.FetchPairData(ctx, ticker)
+}
+
+// IsExchangePairValid checks if the pair exists on the given exchange
+func (r *Registry) IsExchangePairValid(exchange, pair string) (bool, error) {
+	provider, err := r.Provider(exchange)
+	if err != nil {
+		return false, err
+	}
+
+	return provider.IsPairValid(pair)
+}
+
+// HasLastPrice checks if the exchange provides the last trade price of its pairs
+func (r *Registry) HasLastPrice(exchange string) bool {
+	provider, err := r.Provider(exchange)
+	if err != nil {
+		return false
+	}
+
+	lastPriceProvider, ok := provider.(LastPriceProvider)
+
+	return ok && lastPriceProvider.HasLastPrice()
+}
+
+// PairCurrency returns the currency a validated pair of the exchange is quoted in, empty when the exchange doesn't know it
+func (r *Registry) PairCurrency(exchange, pair string) string {
+	provider, err := r.Provider(exchange)
+	if err != nil {
+		return ""
+	}
+
+	currencyProvider, ok := provider.(CurrencyProvider)
+	if !ok {
+		return ""
+	}
+
+	return currencyProvider.PairCurrency(pair)
+}
+
+// normalizeExchange returns the registry key for the exchange name
+func normalizeExchange(exchange string) string {
+	exchange = strings.ToLower(strings.TrimSpace(exchange))
+	if exchange == "" {
+		return DefaultExchange
+	}
+
+	return exchange
+}
@@ -0,0 +1,104 @@
+package api
+
+import (
+	"context"
+	"testing"
+
+	"crypto-alert-bot/internal/models"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeProvider is a Provider that records the fetched pairs
+type fakeProvider struct {
+	ask     models.Float64
+	fetched []string
+}
+
+func (f *fakeProvider) FetchPairData(_ context.Context, ticker *models.Ticker) error {
+	f.fetched = append(f.fetched, ticker.Pair)
+	ticker.CurrentAsk = f.ask
+
+	return nil
+}
+
+func (f *fakeProvider) IsPairValid(pair string) (bool, error) {
+	if pair == "FAKE" {
+		return false, errors.New("pair doesn't exist, try again")
+	}
+
+	return true, nil
+}
+
+// fakeLastPriceProvider is a fakeProvider that also provides the last trade price
+type fakeLastPriceProvider struct {
+	fakeProvider
+}
+
+func (f *fakeLastPriceProvider) HasLastPrice() bool {
+	return true
+}
+
+// fakeCurrencyProvider is a fakeProvider that knows the quote currency of the pairs
+type fakeCurrencyProvider struct {
+	fakeProvider
+}
+
+func (f *fakeCurrencyProvider) PairCurrency(pair string) string {
+	return "USD"
+}
+
+func TestRegistry(t *testing.T) {
+	uphold := &fakeProvider{ask: 1}
+	kraken := &fakeLastPriceProvider{fakeProvider{ask: 2}}
+
+	registry := NewRegistry()
+	registry.Register("uphold", uphold)
+	registry.Register("Kraken", kraken)
+
+	t.Run("Routes tickers to their exchange", func(t *testing.T) {
+		btc := &models.Ticker{Pair: "BTCUSD"}
+		eth := &models.Ticker{Pair: "ETH-EUR", Exchange: "kraken"}
+
+		assert.NoError(t, registry.FetchPairData(context.Background(), btc))
+		assert.NoError(t, registry.FetchPairData(context.Background(), eth))
+
+		assert.Equal(t, []string{"BTCUSD"}, uphold.fetched)
+		assert.Equal(t, []string{"ETH-EUR"}, kraken.fetched)
+		assert.Equal(t, 1.0, btc.CurrentAsk.Float64())
+		assert.Equal(t, 2.0, eth.CurrentAsk.Float64())
+	})
+
+	t.Run("Unknown exchange", func(t *testing.T) {
+		err := registry.FetchPairData(context.Background(), &models.Ticker{Pair: "BTCUSD", Exchange: "mtgox"})
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), `unknown exchange "mtgox", supported exchanges are kraken, uphold`)
+	})
+
+	t.Run("Validates pairs on the given exchange", func(t *testing.T) {
+		valid, err := registry.IsExchangePairValid("KRAKEN", "BTC-USD")
+		assert.True(t, valid)
+		assert.NoError(t, err)
+
+		valid, err = registry.IsExchangePairValid("", "FAKE")
+		assert.False(t, valid)
+		assert.Error(t, err)
+	})
+
+	t.Run("Reports the exchanges with last trade prices", func(t *testing.T) {
+		assert.True(t, registry.HasLastPrice("kraken"))
+		assert.False(t, registry.HasLastPrice("uphold"))
+		assert.False(t, registry.HasLastPrice("mtgox"))
+	})
+
+	t.Run("Resolves the quote currency of the exchanges knowing it", func(t *testing.T) {
+		registry := NewRegistry()
+		registry.Register("uphold", &fakeCurrencyProvider{})
+		registry.Register("kraken", &fakeProvider{})
+
+		assert.Equal(t, "USD", registry.PairCurrency("", "BTCUSD"))
+		assert.Equal(t, "", registry.PairCurrency("kraken", "BTCUSD"))
+		assert.Equal(t, "", registry.PairCurrency("mtgox", "BTCUSD"))
+	})
+}
@@ -9,13 +9,16 @@ import (
 	"io"
 	"net/http"
 	"crypto-alert-bot/internal/models"
+	"sync"
 )
 
 var PublicURLTicker = "https://api.uphold.com/v0/ticker"
 
 // UpholdApi represents the API response
 type UpholdApi struct {
-	client *http.Client
+	client     *http.Client
+	mu         sync.Mutex
+	currencies map[string]string
 }
 
 // NewUpholdApi returns an new instance of UpholdApi
@@ -25,7 +28,8 @@ func NewUpholdApi(client *http.Client) *UpholdApi {
 	}
 
 	return &UpholdApi{
-		client: client,
+		client:     client,
+		currencies: make(map[string]string),
 	}
 }
 
@@ -45,7 +49,7 @@ func (a *UpholdApi) FetchPairData(ctx context.Context, ticker *models.Ticker) er
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return errors.Errorf("unexpected status code: %d", resp.StatusCode)
+		return newStatusError(resp)
 	}
 
 	err = a.ParseAPIData(resp, ticker)
@@ -71,7 +75,7 @@ func (a *UpholdApi) ParseAPIData(response *http.Response, ticker *models.Ticker)
 	return nil
 }
 
-// IsPairValid checks if the pair exists and if it's a single pair
+// IsPairValid checks if the pair exists and if it's a single pair, remembering the currency it's quoted in
 func (a *UpholdApi) IsPairValid(pair string) (bool, error) {
 	pairUrl := fmt.Sprintf(PublicURLTicker+"/%s", pair)
 
@@ -99,5 +103,60 @@ func (a *UpholdApi) IsPairValid(pair string) (bool, error) {
 		return false, errors.Errorf("cant use %s because it returns more than one pair. Please specify a ticker for a single pair", pair)
 	}
 
+	var data struct {
+		Currency string `json:"currency"`
+	}
+
+	if json.Unmarshal(respBody, &data) == nil && data.Currency != "" {
+		a.mu.Lock()
+		a.currencies[models.CompactPair(pair)] = data.Currency
+		a.mu.Unlock()
+	}
+
 	return true, nil
 }
+
+// PairCurrency returns the currency a validated pair is quoted in, empty when unknown
+func (a *UpholdApi) PairCurrency(pair string) string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return a.currencies[models.CompactPair(pair)]
+}
+
+// FetchAllTickers fetches the data for every pair available on the API
+func (a *UpholdApi) FetchAllTickers(ctx context.Context) (models.Tickers, error) {
+	return a.fetchTickers(ctx, PublicURLTicker)
+}
+
+// FetchCurrencyTickers fetches, on a single request, the data for every pair quoted in the given currency
+func (a *UpholdApi) FetchCurrencyTickers(ctx context.Context, currency string) (models.Tickers, error) {
+	return a.fetchTickers(ctx, fmt.Sprintf(PublicURLTicker+"/%s", currency))
+}
+
+// fetchTickers fetches an API endpoint returning a list of tickers
+func (a *UpholdApi) fetchTickers(ctx context.Context, url string) (models.Tickers, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating api request")
+	}
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newStatusError(resp)
+	}
+
+	var tickers models.Tickers
+
+	err = json.NewDecoder(resp.Body).Decode(&tickers)
+	if err != nil {
+		return nil, errors.Wrap(err, "error unmarshalling api response")
+	}
+
+	return tickers, nil
+}
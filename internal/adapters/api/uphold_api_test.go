@@ -7,8 +7,10 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"crypto-alert-bot/internal/models"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -132,6 +134,7 @@ func TestIsPairValid(t *testing.T) {
 		statusCode   int
 		responseBody string
 		wantValid    bool
+		wantCurrency string
 		wantErr      bool
 		errContains  string
 	}{
@@ -156,6 +159,13 @@ func TestIsPairValid(t *testing.T) {
 			wantValid:    true,
 			wantErr:      false,
 		},
+		{
+			name:         "Remembers the quote currency",
+			statusCode:   http.StatusOK,
+			responseBody: `{"ask": "1", "bid": "1", "currency": "USD"}`,
+			wantValid:    true,
+			wantCurrency: "USD",
+		},
 		{
 			name:         "Multiple pairs returned",
 			statusCode:   http.StatusOK,
@@ -185,6 +195,7 @@ func TestIsPairValid(t *testing.T) {
 			valid, err := a.IsPairValid("BTC")
 
 			assert.Equal(t, tt.wantValid, valid)
+			assert.Equal(t, tt.wantCurrency, a.PairCurrency("BTC"))
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -197,3 +208,106 @@ func TestIsPairValid(t *testing.T) {
 		})
 	}
 }
+
+func TestFetchAllTickers(t *testing.T) {
+	tests := []struct {
+		name         string
+		statusCode   int
+		responseBody string
+		wantPairs    []string
+		wantErr      bool
+		errContains  string
+	}{
+		{
+			name:         "Success - 200 OK with valid JSON",
+			statusCode:   http.StatusOK,
+			responseBody: `[{"ask":"123.45","bid":"120.00","currency":"USD","pair":"BTCUSD"},{"ask":"2.5","bid":"2.4","currency":"EUR","pair":"ETHEUR"}]`,
+			wantPairs:    []string{"BTCUSD", "ETHEUR"},
+		},
+		{
+			name:        "Error - non-OK status code (500)",
+			statusCode:  http.StatusInternalServerError,
+			wantErr:     true,
+			errContains: "unexpected status code: 500",
+		},
+		{
+			name:         "Error - invalid JSON",
+			statusCode:   http.StatusOK,
+			responseBody: `[{"ask":"badjson"`,
+			wantErr:      true,
+			errContains:  "error unmarshalling api response",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.statusCode)
+				_, _ = w.Write([]byte(tt.responseBody))
+			}))
+			defer server.Close()
+
+			oldURL := PublicURLTicker
+			PublicURLTicker = server.URL
+			defer func() { PublicURLTicker = oldURL }()
+
+			a := NewUpholdApi(nil)
+
+			tickers, err := a.FetchAllTickers(context.Background())
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errContains)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Len(t, tickers, len(tt.wantPairs))
+			for i, pair := range tt.wantPairs {
+				assert.Equal(t, pair, tickers[i].Pair)
+			}
+			assert.Equal(t, "USD", tickers[0].Currency)
+			assert.Equal(t, 123.45, tickers[0].CurrentAsk.Float64())
+		})
+	}
+}
+
+func TestStatusError(t *testing.T) {
+	tests := []struct {
+		name          string
+		statusCode    int
+		retryAfter    string
+		wantRetryable bool
+		wantWait      time.Duration
+	}{
+		{name: "Not found is not retryable", statusCode: http.StatusNotFound},
+		{name: "Server error is retryable", statusCode: http.StatusBadGateway, wantRetryable: true},
+		{name: "Too many requests is retryable", statusCode: http.StatusTooManyRequests, retryAfter: "7", wantRetryable: true, wantWait: 7 * time.Second},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.retryAfter != "" {
+					w.Header().Set("Retry-After", tt.retryAfter)
+				}
+				w.WriteHeader(tt.statusCode)
+			}))
+			defer server.Close()
+
+			oldURL := PublicURLTicker
+			PublicURLTicker = server.URL
+			defer func() { PublicURLTicker = oldURL }()
+
+			err := NewUpholdApi(nil).FetchPairData(context.Background(), &models.Ticker{Pair: "BTCUSD"})
+
+			var statusErr *StatusError
+			assert.True(t, errors.As(err, &statusErr))
+			assert.Equal(t, tt.statusCode, statusErr.StatusCode)
+			assert.Equal(t, tt.wantRetryable, statusErr.Retryable())
+			assert.Equal(t, tt.wantWait, statusErr.RetryAfter)
+		})
+	}
+}
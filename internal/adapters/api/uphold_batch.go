@@ -0,0 +1,92 @@
+package api
+
+import (
+	"context"
+	"crypto-alert-bot/internal/models"
+	"sync"
+	"time"
+)
+
+// UpholdBatchApi shares a single /ticker/:currency request between the tickers quoted in the same currency
+// and refreshed at the same rate, instead of requesting each pair individually
+type UpholdBatchApi struct {
+	*UpholdApi
+	mu      sync.Mutex
+	batches map[batchKey]*batch
+}
+
+// batchKey groups the tickers served by the same request
+type batchKey struct {
+	currency    string
+	refreshRate float64
+}
+
+// batch holds the last response of a currency request, keyed by compact pair
+type batch struct {
+	mu        sync.Mutex
+	fetchedAt time.Time
+	tickers   map[string]*models.Ticker
+}
+
+// NewUpholdBatchApi returns a new instance of UpholdBatchApi
+func NewUpholdBatchApi(upholdApi *UpholdApi) *UpholdBatchApi {
+	return &UpholdBatchApi{
+		UpholdApi: upholdApi,
+		batches:   make(map[batchKey]*batch),
+	}
+}
+
+// FetchPairData fetches the data for a given pair from the batch of its quote currency.
+// The batch response is reused for half of the refresh interval, so tickers firing on the same tick share it.
+// Tickers with an unknown quote currency are fetched individually, which fills the currency for the next fetches
+func (b *UpholdBatchApi) FetchPairData(ctx context.Context, ticker *models.Ticker) error {
+	currency := ticker.QuoteCurrency()
+	if currency == "" {
+		return b.UpholdApi.FetchPairData(ctx, ticker)
+	}
+
+	current := b.batch(batchKey{currency: currency, refreshRate: ticker.Config.RefreshRate})
+
+	current.mu.Lock()
+	defer current.mu.Unlock()
+
+	maxAge := time.Duration(ticker.Config.RefreshRate * float64(time.Second) / 2)
+
+	if current.tickers == nil || time.Since(current.fetchedAt) >= maxAge {
+		tickers, err := b.FetchCurrencyTickers(ctx, currency)
+		if err != nil {
+			return err
+		}
+
+		current.tickers = make(map[string]*models.Ticker, len(tickers))
+		for _, t := range tickers {
+			current.tickers[models.CompactPair(t.Pair)] = t
+		}
+		current.fetchedAt = time.Now()
+	}
+
+	data, ok := current.tickers[models.CompactPair(ticker.Pair)]
+	if !ok {
+		return b.UpholdApi.FetchPairData(ctx, ticker)
+	}
+
+	ticker.CurrentAsk = data.CurrentAsk
+	ticker.CurrentBid = data.CurrentBid
+	ticker.Currency = data.Currency
+
+	return nil
+}
+
+// batch returns the batch for the key, creating it if needed
+func (b *UpholdBatchApi) batch(key batchKey) *batch {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	current, ok := b.batches[key]
+	if !ok {
+		current = &batch{}
+		b.batches[key] = current
+	}
+
+	return current
+}
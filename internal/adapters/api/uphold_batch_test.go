@@ -0,0 +1,83 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"crypto-alert-bot/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpholdBatchFetchPairData(t *testing.T) {
+	var mu sync.Mutex
+	requests := make(map[string]int)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests[r.URL.Path]++
+		mu.Unlock()
+
+		switch r.URL.Path {
+		case "/USD":
+			_, _ = w.Write([]byte(`[{"ask":"100","bid":"99","currency":"USD","pair":"BTCUSD"},{"ask":"10","bid":"9","currency":"USD","pair":"ETHUSD"}]`))
+		case "/XRPEUR":
+			_, _ = w.Write([]byte(`{"ask":"1.5","bid":"1.4","currency":"EUR"}`))
+		case "/LTC-USD":
+			_, _ = w.Write([]byte(`{"ask":"50","bid":"49","currency":"USD"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	oldURL := PublicURLTicker
+	PublicURLTicker = server.URL
+	defer func() { PublicURLTicker = oldURL }()
+
+	batchApi := NewUpholdBatchApi(NewUpholdApi(nil))
+	ctx := context.Background()
+
+	t.Run("Tickers with the same currency and refresh rate share one request", func(t *testing.T) {
+		btc := &models.Ticker{Pair: "BTC-USD", Config: models.TickerConfig{RefreshRate: 60}}
+		eth := &models.Ticker{Pair: "ETHUSD", Currency: "USD", Config: models.TickerConfig{RefreshRate: 60}}
+
+		var wg sync.WaitGroup
+		for _, ticker := range []*models.Ticker{btc, eth} {
+			wg.Add(1)
+			go func(ticker *models.Ticker) {
+				defer wg.Done()
+				assert.NoError(t, batchApi.FetchPairData(ctx, ticker))
+			}(ticker)
+		}
+		wg.Wait()
+
+		assert.Equal(t, 1, requests["/USD"])
+		assert.Equal(t, 100.0, btc.CurrentAsk.Float64())
+		assert.Equal(t, 99.0, btc.CurrentBid.Float64())
+		assert.Equal(t, "USD", btc.Currency)
+		assert.Equal(t, 10.0, eth.CurrentAsk.Float64())
+	})
+
+	t.Run("Tickers with an unknown currency are fetched individually", func(t *testing.T) {
+		xrp := &models.Ticker{Pair: "XRPEUR", Config: models.TickerConfig{RefreshRate: 60}}
+
+		assert.NoError(t, batchApi.FetchPairData(ctx, xrp))
+
+		assert.Equal(t, 1, requests["/XRPEUR"])
+		assert.Equal(t, "EUR", xrp.Currency)
+		assert.Equal(t, 1.5, xrp.CurrentAsk.Float64())
+	})
+
+	t.Run("Pairs missing from the batch fall back to a single request", func(t *testing.T) {
+		ltc := &models.Ticker{Pair: "LTC-USD", Config: models.TickerConfig{RefreshRate: 60}}
+
+		assert.NoError(t, batchApi.FetchPairData(ctx, ltc))
+
+		assert.Equal(t, 1, requests["/USD"])
+		assert.Equal(t, 1, requests["/LTC-USD"])
+		assert.Equal(t, 50.0, ltc.CurrentAsk.Float64())
+	})
+}
@@ -0,0 +1,94 @@
+package chat
+
+import (
+	"bytes"
+	"context"
+	"crypto-alert-bot/internal/adapters/api"
+	"crypto-alert-bot/internal/models"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const requestTimeout = 10 * time.Second
+
+// message holds the alert details every chat formats in its own way
+type message struct {
+	title       string
+	limitName   string
+	limitValue  string
+	priceAlert  bool
+	exchange    string
+	up          bool
+	percChange  float64
+	priceChange float64
+	price       float64
+	priceSource models.PriceSource
+	indicators  string
+	suppressed  int
+	timestamp   time.Time
+}
+
+// newMessage builds the message of the alert
+func newMessage(alert *models.AlertView) message {
+	return message{
+		title:       alert.Title,
+		limitName:   alert.LimitName,
+		limitValue:  alert.Limit,
+		priceAlert:  alert.PriceAlert,
+		exchange:    alert.Exchange,
+		up:          alert.Direction == models.DirectionUp,
+		percChange:  alert.PercChange,
+		priceChange: alert.PriceChange,
+		price:       alert.Price,
+		priceSource: alert.PriceSource,
+		indicators:  alert.Indicators.String(),
+		suppressed:  alert.Suppressed,
+		timestamp:   alert.Timestamp,
+	}
+}
+
+// suppressedNote returns the note on the alerts suppressed since the previous one, empty when none were
+func (m message) suppressedNote() string {
+	if m.suppressed == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("%d similar alert(s) suppressed since the previous one", m.suppressed)
+}
+
+// postJSON posts the body as JSON to the url, failing on any status code other than 2xx
+func postJSON(client *http.Client, url string, body any) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return errors.Wrap(err, "error marshalling chat message")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return errors.Wrap(err, "error creating chat request")
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "error sending chat message")
+	}
+	defer resp.Body.Close()
+
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return &api.StatusError{StatusCode: resp.StatusCode}
+	}
+
+	return nil
+}
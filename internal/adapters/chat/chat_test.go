@@ -0,0 +1,197 @@
+package chat
+
+import (
+	"crypto-alert-bot/internal/models"
+	"crypto-alert-bot/internal/services"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPublishers(t *testing.T) {
+	tests := []struct {
+		name      string
+		publisher func(url string) services.AlertPublisher
+		wantPath  string
+		assert    func(t *testing.T, body map[string]any)
+	}{
+		{
+			name: "Slack",
+			publisher: func(url string) services.AlertPublisher {
+				return NewSlackPublisher(nil, url+"/services/T000/B000/XXX")
+			},
+			wantPath: "/services/T000/B000/XXX",
+			assert: func(t *testing.T, body map[string]any) {
+				assert.Equal(t, "▼ BTC-USD -2.00%", body["text"])
+				assert.Len(t, body["blocks"], 3)
+			},
+		},
+		{
+			name: "Discord",
+			publisher: func(url string) services.AlertPublisher {
+				return NewDiscordPublisher(nil, url+"/api/webhooks/1/token")
+			},
+			wantPath: "/api/webhooks/1/token",
+			assert: func(t *testing.T, body map[string]any) {
+				embed := body["embeds"].([]any)[0].(map[string]any)
+				assert.Equal(t, "▼ BTC-USD -2.00%", embed["title"])
+				assert.Equal(t, float64(discordColorDown), embed["color"])
+				assert.Len(t, embed["fields"], 4)
+			},
+		},
+		{
+			name: "Telegram",
+			publisher: func(url string) services.AlertPublisher {
+				return NewTelegramPublisher(nil, url, "123:abc", "42")
+			},
+			wantPath: "/bot123:abc/sendMessage",
+			assert: func(t *testing.T, body map[string]any) {
+				assert.Equal(t, "42", body["chat_id"])
+				assert.Equal(t, "HTML", body["parse_mode"])
+				assert.Contains(t, body["text"], "<b>▼ BTC-USD -2.00%</b>")
+				assert.Contains(t, body["text"], "Price (ask): <code>98</code>")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := make(chan *http.Request, 1)
+			bodies := make(chan []byte, 1)
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				requests <- r
+				bodies <- body
+			}))
+			defer server.Close()
+
+			ticker := models.NewTicker("BTC-USD", 5, 1, 0)
+			ticker.PreviousAsk = 100
+			ticker.CurrentAsk = 98
+			ticker.IsAbovePercOscillation()
+
+			tt.publisher(server.URL).Publish(models.NewAlertView(time.Now(), ticker))
+
+			r := <-requests
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.Equal(t, tt.wantPath, r.URL.Path)
+			assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+			var body map[string]any
+			require.NoError(t, json.Unmarshal(<-bodies, &body))
+			tt.assert(t, body)
+		})
+	}
+}
+
+func TestExpressionMessage(t *testing.T) {
+	rule, err := models.NewExpressionRule("spread_pct > 1")
+	require.NoError(t, err)
+
+	ticker := models.NewTicker("ETHEUR", 5, 1, 0)
+	ticker.CurrentAsk = 100
+	ticker.AlertType = models.AlertExpression
+	ticker.Rule = rule
+
+	m := newMessage(models.NewAlertView(time.Now(), ticker))
+	assert.Equal(t, "◆ ETHEUR matched spread_pct > 1", m.title)
+	assert.Equal(t, []string{"Rule", "spread_pct > 1"}, []string{m.limitName, m.limitValue})
+}
+
+func TestRuleMessageEscaping(t *testing.T) {
+	rule, err := models.NewExpressionRule("pct_change(ask, 10m) > 3 && spread_pct < 0.5")
+	require.NoError(t, err)
+
+	ticker := models.NewTicker("ETHEUR", 5, 1, 0)
+	ticker.CurrentAsk = 100
+	ticker.AlertType = models.AlertExpression
+	ticker.Rule = rule
+
+	m := newMessage(models.NewAlertView(time.Now(), ticker))
+
+	text := newTelegramText(m)
+	assert.Contains(t, text, "<b>◆ ETHEUR matched pct_change(ask, 10m) &gt; 3 &amp;&amp; spread_pct &lt; 0.5</b>")
+	assert.Contains(t, text, "Rule: pct_change(ask, 10m) &gt; 3 &amp;&amp; spread_pct &lt; 0.5\n")
+
+	slack := newSlackMessage(m)
+	assert.Equal(t, "◆ ETHEUR matched pct_change(ask, 10m) &gt; 3 &amp;&amp; spread_pct &lt; 0.5", slack.Text)
+	assert.Contains(t, slack.Blocks[1].Fields, slackText{Type: "mrkdwn", Text: "*Rule*\npct_change(ask, 10m) &gt; 3 &amp;&amp; spread_pct &lt; 0.5"})
+}
+
+func TestSpreadMessage(t *testing.T) {
+	ticker := models.NewTicker("ETHEUR", 5, 1, 0)
+	ticker.CurrentAsk = 101
+	ticker.CurrentBid = 99
+	ticker.AlertType = models.AlertSpread
+	ticker.Rule = &models.SpreadRule{MaxPct: 1}
+
+	m := newMessage(models.NewAlertView(time.Now(), ticker))
+	assert.Equal(t, "↔ ETHEUR spread widened to 2%", m.title)
+	assert.Contains(t, newTelegramText(m), "Rule: spread &gt; 1%")
+}
+
+func TestIndicatorMessage(t *testing.T) {
+	rule, _ := models.NewIndicatorRule("rsi(14) > 70")
+
+	ticker := models.NewTicker("BTCUSD", 5, 1, 0)
+	ticker.CurrentAsk = 70000
+	ticker.AlertType = models.AlertIndicator
+	ticker.Rule = rule
+	ticker.Indicators = models.IndicatorValues{"RSI(14)": 72.5}
+
+	m := newMessage(models.NewAlertView(time.Now(), ticker))
+	assert.Equal(t, "∿ BTCUSD RSI(14) > 70", m.title)
+	assert.Contains(t, newTelegramText(m), "Indicators: <code>RSI(14)=72.5</code>")
+	assert.Contains(t, newDiscordMessage(m).Embeds[0].Fields, discordField{Name: "Indicators", Value: "RSI(14)=72.5"})
+}
+
+func TestPegMessage(t *testing.T) {
+	ticker := models.NewTicker("USDTUSD", 5, 0, 0)
+	ticker.CurrentAsk = 0.985
+	ticker.AlertType = models.AlertDepeg
+	ticker.Rule = &models.PegRule{Target: 1, Tolerance: 0.5, For: 5 * time.Minute}
+	ticker.Peg = &models.PegStatus{Target: 1, Tolerance: 0.5, Deviation: -1.5, OutsideSeconds: 330}
+
+	m := newMessage(models.NewAlertView(time.Now(), ticker))
+	assert.Equal(t, "⚓ USDTUSD de-pegged to 0.985, -1.50% from 1 for 5m30s", m.title)
+	assert.Contains(t, newTelegramText(m), "Rule: peg 1 ± 0.5% for 5m0s")
+
+	ticker.AlertType = models.AlertPegRecovered
+	ticker.Peg.Recovered = true
+
+	assert.Equal(t, "⚓ USDTUSD back within 0.5% of its 1 peg after 5m30s", newMessage(models.NewAlertView(time.Now(), ticker)).title)
+}
+
+func TestStaleMessage(t *testing.T) {
+	timestamp := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	ticker := models.NewTicker("BTCUSD", 5, 1, 0)
+	ticker.AlertType = models.AlertStale
+	ticker.Stale = &models.StaleStatus{Reason: models.StaleFrozen, Since: timestamp.Add(-10 * time.Minute)}
+
+	text := newTelegramText(newMessage(models.NewAlertView(timestamp, ticker)))
+	assert.Contains(t, text, "<b>⏸ BTCUSD quotes from uphold unchanged for 10m0s</b>")
+	assert.NotContains(t, text, "Price")
+}
+
+func TestSuppressedNote(t *testing.T) {
+	ticker := models.NewTicker("ETHEUR", 5, 1, 0)
+	ticker.PreviousAsk = 100
+	ticker.CurrentAsk = 105
+	ticker.IsAbovePercOscillation()
+
+	assert.NotContains(t, newTelegramText(newMessage(models.NewAlertView(time.Now(), ticker))), "suppressed")
+
+	ticker.Suppressed = 3
+
+	m := newMessage(models.NewAlertView(time.Now(), ticker))
+	assert.Contains(t, newTelegramText(m), "3 similar alert(s) suppressed since the previous one")
+	assert.Equal(t, "3 similar alert(s) suppressed since the previous one", newDiscordMessage(m).Embeds[0].Footer.Text)
+}
@@ -0,0 +1,95 @@
+package chat
+
+import (
+	"crypto-alert-bot/internal/models"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+const (
+	discordColorUp   = 0x2ecc71
+	discordColorDown = 0xe74c3c
+	discordColorInfo = 0xf1c40f
+)
+
+// DiscordPublisher posts the alerts to a Discord webhook
+type DiscordPublisher struct {
+	client     *http.Client
+	webhookURL string
+}
+
+// discordMessage is the Discord webhook payload
+type discordMessage struct {
+	Content string         `json:"content"`
+	Embeds  []discordEmbed `json:"embeds"`
+}
+
+type discordEmbed struct {
+	Title     string         `json:"title"`
+	Color     int            `json:"color"`
+	Fields    []discordField `json:"fields,omitempty"`
+	Footer    *discordFooter `json:"footer,omitempty"`
+	Timestamp string         `json:"timestamp"`
+}
+
+type discordFooter struct {
+	Text string `json:"text"`
+}
+
+type discordField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline"`
+}
+
+// NewDiscordPublisher returns a new instance of DiscordPublisher posting to the webhook URL
+func NewDiscordPublisher(client *http.Client, webhookURL string) *DiscordPublisher {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return &DiscordPublisher{client: client, webhookURL: webhookURL}
+}
+
+// Publish posts the alert to Discord
+func (p *DiscordPublisher) Publish(alert *models.AlertView) {
+	err := postJSON(p.client, p.webhookURL, newDiscordMessage(newMessage(alert)))
+	if err != nil {
+		slog.Error("error publishing alert to discord", "pair", alert.Pair, "error", err)
+	}
+}
+
+// newDiscordMessage formats the message as a Discord embed, green for price rises and red for drops
+func newDiscordMessage(m message) discordMessage {
+	embed := discordEmbed{
+		Title:     m.title,
+		Color:     discordColorInfo,
+		Timestamp: m.timestamp.Format(time.RFC3339),
+	}
+
+	if m.priceAlert {
+		embed.Color = discordColorDown
+		if m.up {
+			embed.Color = discordColorUp
+		}
+
+		embed.Fields = []discordField{
+			{Name: fmt.Sprintf("Price (%s)", m.priceSource), Value: fmt.Sprintf("%.8g", m.price), Inline: true},
+			{Name: "Change", Value: fmt.Sprintf("%+.8g (%+.2f%%)", m.priceChange, m.percChange), Inline: true},
+			{Name: "Exchange", Value: m.exchange, Inline: true},
+			{Name: m.limitName, Value: m.limitValue, Inline: true},
+		}
+
+		if m.indicators != "" {
+			embed.Fields = append(embed.Fields, discordField{Name: "Indicators", Value: m.indicators})
+		}
+	}
+
+	if note := m.suppressedNote(); note != "" {
+		embed.Footer = &discordFooter{Text: note}
+	}
+
+	return discordMessage{Content: m.title, Embeds: []discordEmbed{embed}}
+}
@@ -0,0 +1,88 @@
+package chat
+
+import (
+	"crypto-alert-bot/internal/models"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// SlackPublisher posts the alerts to a Slack incoming webhook
+type SlackPublisher struct {
+	client     *http.Client
+	webhookURL string
+}
+
+// slackMessage is the Slack incoming webhook payload, text is the fallback shown on notifications
+type slackMessage struct {
+	Text   string       `json:"text"`
+	Blocks []slackBlock `json:"blocks"`
+}
+
+type slackBlock struct {
+	Type     string      `json:"type"`
+	Text     *slackText  `json:"text,omitempty"`
+	Fields   []slackText `json:"fields,omitempty"`
+	Elements []slackText `json:"elements,omitempty"`
+}
+
+type slackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// NewSlackPublisher returns a new instance of SlackPublisher posting to the incoming webhook URL
+func NewSlackPublisher(client *http.Client, webhookURL string) *SlackPublisher {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return &SlackPublisher{client: client, webhookURL: webhookURL}
+}
+
+// Publish posts the alert to Slack
+func (p *SlackPublisher) Publish(alert *models.AlertView) {
+	err := postJSON(p.client, p.webhookURL, newSlackMessage(newMessage(alert)))
+	if err != nil {
+		slog.Error("error publishing alert to slack", "pair", alert.Pair, "error", err)
+	}
+}
+
+// newSlackMessage formats the message as Slack blocks
+func newSlackMessage(m message) slackMessage {
+	blocks := []slackBlock{
+		{Type: "section", Text: &slackText{Type: "mrkdwn", Text: "*" + slackEscape(m.title) + "*"}},
+	}
+
+	if m.priceAlert {
+		blocks = append(blocks, slackBlock{Type: "section", Fields: []slackText{
+			{Type: "mrkdwn", Text: fmt.Sprintf("*Price (%s)*\n%.8g", m.priceSource, m.price)},
+			{Type: "mrkdwn", Text: fmt.Sprintf("*Change*\n%+.8g (%+.2f%%)", m.priceChange, m.percChange)},
+			{Type: "mrkdwn", Text: fmt.Sprintf("*Exchange*\n%s", slackEscape(m.exchange))},
+			{Type: "mrkdwn", Text: fmt.Sprintf("*%s*\n%s", m.limitName, slackEscape(m.limitValue))},
+		}})
+
+		if m.indicators != "" {
+			blocks = append(blocks, slackBlock{Type: "section", Text: &slackText{Type: "mrkdwn", Text: "*Indicators*\n" + slackEscape(m.indicators)}})
+		}
+	}
+
+	context := []slackText{{Type: "mrkdwn", Text: m.timestamp.Format(time.RFC3339)}}
+	if note := m.suppressedNote(); note != "" {
+		context = append(context, slackText{Type: "mrkdwn", Text: note})
+	}
+
+	blocks = append(blocks, slackBlock{Type: "context", Elements: context})
+
+	return slackMessage{Text: slackEscape(m.title), Blocks: blocks}
+}
+
+// slackEscaper escapes the characters Slack mrkdwn treats as control characters
+var slackEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// slackEscape escapes the text shown on a Slack mrkdwn block
+func slackEscape(text string) string {
+	return slackEscaper.Replace(text)
+}
@@ -0,0 +1,83 @@
+package chat
+
+import (
+	"crypto-alert-bot/internal/models"
+	"fmt"
+	"html"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// TelegramURLBase is the default Telegram Bot API base URL
+var TelegramURLBase = "https://api.telegram.org"
+
+// TelegramPublisher sends the alerts to a Telegram chat through the Bot API
+type TelegramPublisher struct {
+	client  *http.Client
+	baseURL string
+	token   string
+	chatID  string
+}
+
+// telegramMessage is the Bot API sendMessage payload
+type telegramMessage struct {
+	ChatID    string `json:"chat_id"`
+	Text      string `json:"text"`
+	ParseMode string `json:"parse_mode"`
+}
+
+// NewTelegramPublisher returns a new instance of TelegramPublisher, using TelegramURLBase when the base URL is empty
+func NewTelegramPublisher(client *http.Client, baseURL, token, chatID string) *TelegramPublisher {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	if baseURL == "" {
+		baseURL = TelegramURLBase
+	}
+
+	return &TelegramPublisher{
+		client:  client,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		token:   token,
+		chatID:  chatID,
+	}
+}
+
+// Publish sends the alert to the Telegram chat
+func (p *TelegramPublisher) Publish(alert *models.AlertView) {
+	url := fmt.Sprintf("%s/bot%s/sendMessage", p.baseURL, p.token)
+
+	err := postJSON(p.client, url, telegramMessage{ChatID: p.chatID, Text: newTelegramText(newMessage(alert)), ParseMode: "HTML"})
+	if err != nil {
+		slog.Error("error publishing alert to telegram", "pair", alert.Pair, "error", err)
+	}
+}
+
+// newTelegramText formats the message as Telegram HTML
+func newTelegramText(m message) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "<b>%s</b>\n", html.EscapeString(m.title))
+
+	if m.priceAlert {
+		fmt.Fprintf(&b, "Price (%s): <code>%.8g</code>\n", m.priceSource, m.price)
+		fmt.Fprintf(&b, "Change: <code>%+.8g (%+.2f%%)</code>\n", m.priceChange, m.percChange)
+		fmt.Fprintf(&b, "Exchange: %s\n", html.EscapeString(m.exchange))
+		fmt.Fprintf(&b, "%s: %s\n", m.limitName, html.EscapeString(m.limitValue))
+
+		if m.indicators != "" {
+			fmt.Fprintf(&b, "Indicators: <code>%s</code>\n", html.EscapeString(m.indicators))
+		}
+	}
+
+	if note := m.suppressedNote(); note != "" {
+		fmt.Fprintf(&b, "%s\n", note)
+	}
+
+	fmt.Fprintf(&b, "<i>%s</i>", m.timestamp.Format(time.RFC3339))
+
+	return b.String()
+}
@@ -0,0 +1,288 @@
+package email
+
+import (
+	"bytes"
+	"context"
+	"crypto-alert-bot/internal/models"
+	"crypto/tls"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"log/slog"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"sync"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const sendTimeout = 30 * time.Second
+
+//go:embed templates/*.tmpl
+var templatesFS embed.FS
+
+var (
+	textTemplate = texttemplate.Must(texttemplate.ParseFS(templatesFS, "templates/alerts.txt.tmpl"))
+	htmlTemplate = htmltemplate.Must(htmltemplate.ParseFS(templatesFS, "templates/alerts.html.tmpl"))
+)
+
+// Config holds the SMTP server settings, authenticating only when a username is given
+type Config struct {
+	Addr      string
+	Username  string
+	Password  string
+	From      string
+	StartTLS  bool
+	TLSConfig *tls.Config
+}
+
+// Alert is the view of an alert rendered by the templates
+type Alert struct {
+	Title       string
+	Pair        string
+	Exchange    string
+	PriceAlert  bool
+	RuleAlert   bool
+	Up          bool
+	Price       float64
+	PriceSource models.PriceSource
+	PriceChange float64
+	PercChange  float64
+	LimitName   string
+	Limit       string
+	Indicators  string
+	Suppressed  int
+	Timestamp   time.Time
+}
+
+// Color returns the color the alert title is rendered with, green for price rises and red for drops
+func (a Alert) Color() string {
+	switch {
+	case !a.PriceAlert || a.RuleAlert:
+		return "#b7950b"
+	case a.Up:
+		return "#1e8449"
+	default:
+		return "#c0392b"
+	}
+}
+
+// Publisher emails the alerts to the recipients, either immediately or batched into a periodic digest per recipient
+type Publisher struct {
+	config     Config
+	recipients []string
+	digest     time.Duration
+	mu         sync.Mutex
+	pending    map[string][]Alert
+}
+
+// NewPublisher returns a new instance of Publisher, sending every alert immediately when the digest interval is 0
+func NewPublisher(config Config, recipients []string, digest time.Duration) *Publisher {
+	return &Publisher{
+		config:     config,
+		recipients: recipients,
+		digest:     digest,
+		pending:    make(map[string][]Alert),
+	}
+}
+
+// NewAlert builds the email view of the alert
+func NewAlert(view *models.AlertView) Alert {
+	return Alert{
+		Title:       view.Title,
+		Pair:        view.Pair,
+		Exchange:    view.Exchange,
+		PriceAlert:  view.PriceAlert,
+		RuleAlert:   view.RuleAlert,
+		Up:          view.Direction == models.DirectionUp,
+		Price:       view.Price,
+		PriceSource: view.PriceSource,
+		PriceChange: view.PriceChange,
+		PercChange:  view.PercChange,
+		LimitName:   view.LimitName,
+		Limit:       view.Limit,
+		Indicators:  view.Indicators.String(),
+		Suppressed:  view.Suppressed,
+		Timestamp:   view.Timestamp,
+	}
+}
+
+// Publish emails the alert to every recipient, or queues it for the next digest
+func (p *Publisher) Publish(view *models.AlertView) {
+	alert := NewAlert(view)
+
+	if p.digest <= 0 {
+		for _, recipient := range p.recipients {
+			if err := p.send(recipient, []Alert{alert}); err != nil {
+				slog.Error("error emailing alert", "recipient", recipient, "pair", alert.Pair, "error", err)
+			}
+		}
+
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, recipient := range p.recipients {
+		p.pending[recipient] = append(p.pending[recipient], alert)
+	}
+}
+
+// Run sends the digests every interval until the context is done, flushing the pending alerts before returning
+func (p *Publisher) Run(ctx context.Context) {
+	if p.digest <= 0 {
+		return
+	}
+
+	timeTicker := time.NewTicker(p.digest)
+	defer timeTicker.Stop()
+
+	for {
+		select {
+		case <-timeTicker.C:
+			p.Flush()
+		case <-ctx.Done():
+			p.Flush()
+			return
+		}
+	}
+}
+
+// Flush sends a digest with the pending alerts to each recipient
+func (p *Publisher) Flush() {
+	p.mu.Lock()
+	pending := p.pending
+	p.pending = make(map[string][]Alert)
+	p.mu.Unlock()
+
+	for recipient, alerts := range pending {
+		if err := p.send(recipient, alerts); err != nil {
+			slog.Error("error emailing alerts digest", "recipient", recipient, "alerts", len(alerts), "error", err)
+		}
+	}
+}
+
+// send renders the alerts and delivers the email to the recipient
+func (p *Publisher) send(recipient string, alerts []Alert) error {
+	message, err := p.render(recipient, alerts)
+	if err != nil {
+		return err
+	}
+
+	host, _, err := net.SplitHostPort(p.config.Addr)
+	if err != nil {
+		return errors.Wrap(err, "invalid smtp address")
+	}
+
+	conn, err := net.DialTimeout("tcp", p.config.Addr, sendTimeout)
+	if err != nil {
+		return errors.Wrap(err, "error connecting to smtp server")
+	}
+
+	conn.SetDeadline(time.Now().Add(sendTimeout))
+
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return errors.Wrap(err, "error starting smtp session")
+	}
+	defer client.Close()
+
+	if p.config.StartTLS {
+		tlsConfig := p.config.TLSConfig
+		if tlsConfig == nil {
+			tlsConfig = &tls.Config{ServerName: host}
+		}
+
+		if err = client.StartTLS(tlsConfig); err != nil {
+			return errors.Wrap(err, "error starting tls")
+		}
+	}
+
+	if p.config.Username != "" {
+		if err = client.Auth(smtp.PlainAuth("", p.config.Username, p.config.Password, host)); err != nil {
+			return errors.Wrap(err, "error authenticating on smtp server")
+		}
+	}
+
+	if err = client.Mail(p.config.From); err != nil {
+		return errors.Wrap(err, "error setting sender")
+	}
+
+	if err = client.Rcpt(recipient); err != nil {
+		return errors.Wrap(err, "error setting recipient")
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return errors.Wrap(err, "error starting email data")
+	}
+
+	if _, err = writer.Write(message); err != nil {
+		return errors.Wrap(err, "error writing email data")
+	}
+
+	if err = writer.Close(); err != nil {
+		return errors.Wrap(err, "error sending email")
+	}
+
+	return client.Quit()
+}
+
+// render builds the multipart email with the plain text and HTML versions of the alerts
+func (p *Publisher) render(recipient string, alerts []Alert) ([]byte, error) {
+	subject := alerts[0].Title
+	if len(alerts) > 1 {
+		subject = fmt.Sprintf("Crypto alerts digest: %d alerts", len(alerts))
+	}
+
+	var body bytes.Buffer
+
+	parts := multipart.NewWriter(&body)
+
+	text, err := parts.CreatePart(textproto.MIMEHeader{"Content-Type": {"text/plain; charset=UTF-8"}})
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating text part")
+	}
+
+	if err = textTemplate.Execute(text, alerts); err != nil {
+		return nil, errors.Wrap(err, "error rendering text template")
+	}
+
+	html, err := parts.CreatePart(textproto.MIMEHeader{"Content-Type": {"text/html; charset=UTF-8"}})
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating html part")
+	}
+
+	if err = htmlTemplate.Execute(html, alerts); err != nil {
+		return nil, errors.Wrap(err, "error rendering html template")
+	}
+
+	if err = parts.Close(); err != nil {
+		return nil, errors.Wrap(err, "error closing email parts")
+	}
+
+	headers := []string{
+		"From: " + p.config.From,
+		"To: " + recipient,
+		"Subject: " + mime.QEncoding.Encode("utf-8", subject),
+		"Date: " + time.Now().Format(time.RFC1123Z),
+		"MIME-Version: 1.0",
+		"Content-Type: multipart/alternative; boundary=" + parts.Boundary(),
+	}
+
+	var message bytes.Buffer
+
+	message.WriteString(strings.Join(headers, "\r\n"))
+	message.WriteString("\r\n\r\n")
+	message.Write(body.Bytes())
+
+	return message.Bytes(), nil
+}
@@ -0,0 +1,167 @@
+package email
+
+import (
+	"bufio"
+	"crypto-alert-bot/internal/models"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sinkMessage is an email received by the smtp sink
+type sinkMessage struct {
+	to   string
+	data string
+}
+
+// newSMTPSink starts a local smtp server that accepts every email and sends it to the returned channel
+func newSMTPSink(t *testing.T) (string, <-chan sinkMessage) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	messages := make(chan sinkMessage, 10)
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			go serveSMTP(conn, messages)
+		}
+	}()
+
+	return listener.Addr().String(), messages
+}
+
+func serveSMTP(conn net.Conn, messages chan<- sinkMessage) {
+	defer conn.Close()
+
+	text := textproto.NewConn(conn)
+	text.PrintfLine("220 sink ready")
+
+	var message sinkMessage
+
+	for {
+		line, err := text.ReadLine()
+		if err != nil {
+			return
+		}
+
+		command := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+
+		switch command {
+		case "EHLO", "HELO":
+			text.PrintfLine("250 sink")
+		case "RCPT":
+			message.to = strings.Trim(strings.TrimPrefix(line, "RCPT TO:"), "<>")
+			text.PrintfLine("250 ok")
+		case "DATA":
+			text.PrintfLine("354 go ahead")
+
+			data, _ := io.ReadAll(text.DotReader())
+			message.data = string(data)
+			messages <- message
+
+			text.PrintfLine("250 ok")
+		case "QUIT":
+			text.PrintfLine("221 bye")
+			return
+		default:
+			text.PrintfLine("250 ok")
+		}
+	}
+}
+
+// parts parses the email and returns the body of each part by content type
+func parts(t *testing.T, data string) (*mail.Message, map[string]string) {
+	message, err := mail.ReadMessage(strings.NewReader(data))
+	require.NoError(t, err)
+
+	_, params, err := mime.ParseMediaType(message.Header.Get("Content-Type"))
+	require.NoError(t, err)
+
+	bodies := make(map[string]string)
+
+	reader := multipart.NewReader(message.Body, params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err != nil {
+			break
+		}
+
+		body, _ := io.ReadAll(bufio.NewReader(part))
+		bodies[strings.SplitN(part.Header.Get("Content-Type"), ";", 2)[0]] = string(body)
+	}
+
+	return message, bodies
+}
+
+func newAlertTicker(pair string, previous, current models.Float64) *models.Ticker {
+	ticker := models.NewTicker(pair, 5, 1, 0)
+	ticker.PreviousAsk = previous
+	ticker.CurrentAsk = current
+	ticker.IsAbovePercOscillation()
+
+	return ticker
+}
+
+func TestPublisher(t *testing.T) {
+	t.Run("Sends every alert immediately to each recipient", func(t *testing.T) {
+		addr, messages := newSMTPSink(t)
+
+		publisher := NewPublisher(Config{Addr: addr, From: "bot@example.com"}, []string{"a@example.com", "b@example.com"}, 0)
+		publisher.Publish(models.NewAlertView(time.Now(), newAlertTicker("BTC-USD", 100, 102)))
+
+		var recipients []string
+		for i := 0; i < 2; i++ {
+			message := <-messages
+			recipients = append(recipients, message.to)
+
+			header, bodies := parts(t, message.data)
+
+			subject, err := new(mime.WordDecoder).DecodeHeader(header.Header.Get("Subject"))
+			assert.NoError(t, err)
+			assert.Equal(t, "▲ BTC-USD +2.00%", subject)
+			assert.Contains(t, bodies["text/plain"], "Change:    +2 (+2.00%)")
+			assert.Contains(t, bodies["text/plain"], "Threshold: 1%")
+			assert.Contains(t, bodies["text/html"], "<h3 style=\"color: #1e8449\">▲ BTC-USD &#43;2.00%</h3>")
+		}
+
+		assert.ElementsMatch(t, []string{"a@example.com", "b@example.com"}, recipients)
+	})
+
+	t.Run("Batches the alerts into a digest per recipient", func(t *testing.T) {
+		addr, messages := newSMTPSink(t)
+
+		publisher := NewPublisher(Config{Addr: addr, From: "bot@example.com"}, []string{"a@example.com"}, time.Hour)
+		publisher.Publish(models.NewAlertView(time.Now(), newAlertTicker("BTC-USD", 100, 102)))
+		publisher.Publish(models.NewAlertView(time.Now(), newAlertTicker("ETH-EUR", 100, 98)))
+
+		assert.Empty(t, messages)
+
+		publisher.Flush()
+
+		message := <-messages
+		assert.Equal(t, "a@example.com", message.to)
+
+		header, bodies := parts(t, message.data)
+		assert.Equal(t, "Crypto alerts digest: 2 alerts", header.Header.Get("Subject"))
+		assert.Contains(t, bodies["text/plain"], "▲ BTC-USD +2.00%")
+		assert.Contains(t, bodies["text/plain"], "▼ ETH-EUR -2.00%")
+
+		publisher.Flush()
+		assert.Empty(t, messages, "an empty digest is not sent")
+	})
+}
@@ -1,9 +1,10 @@
 package logger
 
 import (
+	"context"
 	"crypto-alert-bot/internal/models"
 	"log/slog"
-	"time"
+	"strings"
 )
 
 // TickerPublisher is a struct that implements the Publisher
@@ -14,11 +15,38 @@ func NewTickerPublisher() *TickerPublisher {
 	return &TickerPublisher{}
 }
 
-// Publish publishes the ticker
-func (tp *TickerPublisher) Publish(timestamp time.Time, ticker *models.Ticker) {
-	slog.Info(
-		"Above threshold alert:", "pair", ticker.Pair,
-		"percent_change:", ticker.AskPercChange,
-		"price_change:", ticker.AskPriceChange,
-		"time:", timestamp)
+// Publish logs the alert with its title, as a warning when the data source is down or stale
+func (tp *TickerPublisher) Publish(alert *models.AlertView) {
+	level := slog.LevelInfo
+	if alert.Type == models.AlertSourceDown || alert.Type == models.AlertStale {
+		level = slog.LevelWarn
+	}
+
+	attrs := []any{
+		"alert_type", alert.Type,
+		"pair", alert.Pair,
+		"exchange", alert.Exchange,
+	}
+
+	if alert.PriceAlert {
+		attrs = append(attrs,
+			"price_source", alert.PriceSource,
+			"price", alert.Price,
+			"price_change", alert.PriceChange,
+			"percent_change", alert.PercChange,
+			"direction", alert.Direction,
+			strings.ToLower(alert.LimitName), alert.Limit)
+	}
+
+	if len(alert.Indicators) > 0 {
+		attrs = append(attrs, "indicators", alert.Indicators)
+	}
+
+	if alert.Suppressed > 0 {
+		attrs = append(attrs, "suppressed", alert.Suppressed)
+	}
+
+	attrs = append(attrs, "time", alert.Timestamp)
+
+	slog.Log(context.Background(), level, alert.Title, attrs...)
 }
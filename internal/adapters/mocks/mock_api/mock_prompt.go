@@ -53,3 +53,41 @@ func (mr *MockApiDataValidatorMockRecorder) IsPairValid(pair any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsPairValid", reflect.TypeOf((*MockApiDataValidator)(nil).IsPairValid), pair)
 }
+
+// MockCurrencyResolver is a mock of CurrencyResolver interface.
+type MockCurrencyResolver struct {
+	ctrl     *gomock.Controller
+	recorder *MockCurrencyResolverMockRecorder
+	isgomock struct{}
+}
+
+// MockCurrencyResolverMockRecorder is the mock recorder for MockCurrencyResolver.
+type MockCurrencyResolverMockRecorder struct {
+	mock *MockCurrencyResolver
+}
+
+// NewMockCurrencyResolver creates a new mock instance.
+func NewMockCurrencyResolver(ctrl *gomock.Controller) *MockCurrencyResolver {
+	mock := &MockCurrencyResolver{ctrl: ctrl}
+	mock.recorder = &MockCurrencyResolverMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCurrencyResolver) EXPECT() *MockCurrencyResolverMockRecorder {
+	return m.recorder
+}
+
+// PairCurrency mocks base method.
+func (m *MockCurrencyResolver) PairCurrency(pair string) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PairCurrency", pair)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// PairCurrency indicates an expected call of PairCurrency.
+func (mr *MockCurrencyResolverMockRecorder) PairCurrency(pair any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PairCurrency", reflect.TypeOf((*MockCurrencyResolver)(nil).PairCurrency), pair)
+}
@@ -0,0 +1,107 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: watchlist.go
+//
+// Generated by this command:
+//
+//	mockgen -source=watchlist.go -destination=../mocks/mock_watchlist/mock_watchlist.go
+//
+
+// Package mock_watchlist is a generated GoMock package.
+package mock_watchlist
+
+import (
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockApiDataValidator is a mock of ApiDataValidator interface.
+type MockApiDataValidator struct {
+	ctrl     *gomock.Controller
+	recorder *MockApiDataValidatorMockRecorder
+	isgomock struct{}
+}
+
+// MockApiDataValidatorMockRecorder is the mock recorder for MockApiDataValidator.
+type MockApiDataValidatorMockRecorder struct {
+	mock *MockApiDataValidator
+}
+
+// NewMockApiDataValidator creates a new mock instance.
+func NewMockApiDataValidator(ctrl *gomock.Controller) *MockApiDataValidator {
+	mock := &MockApiDataValidator{ctrl: ctrl}
+	mock.recorder = &MockApiDataValidatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockApiDataValidator) EXPECT() *MockApiDataValidatorMockRecorder {
+	return m.recorder
+}
+
+// HasLastPrice mocks base method.
+func (m *MockApiDataValidator) HasLastPrice(exchange string) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasLastPrice", exchange)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// HasLastPrice indicates an expected call of HasLastPrice.
+func (mr *MockApiDataValidatorMockRecorder) HasLastPrice(exchange any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasLastPrice", reflect.TypeOf((*MockApiDataValidator)(nil).HasLastPrice), exchange)
+}
+
+// IsExchangePairValid mocks base method.
+func (m *MockApiDataValidator) IsExchangePairValid(exchange, pair string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsExchangePairValid", exchange, pair)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsExchangePairValid indicates an expected call of IsExchangePairValid.
+func (mr *MockApiDataValidatorMockRecorder) IsExchangePairValid(exchange, pair any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsExchangePairValid", reflect.TypeOf((*MockApiDataValidator)(nil).IsExchangePairValid), exchange, pair)
+}
+
+// MockCurrencyResolver is a mock of CurrencyResolver interface.
+type MockCurrencyResolver struct {
+	ctrl     *gomock.Controller
+	recorder *MockCurrencyResolverMockRecorder
+	isgomock struct{}
+}
+
+// MockCurrencyResolverMockRecorder is the mock recorder for MockCurrencyResolver.
+type MockCurrencyResolverMockRecorder struct {
+	mock *MockCurrencyResolver
+}
+
+// NewMockCurrencyResolver creates a new mock instance.
+func NewMockCurrencyResolver(ctrl *gomock.Controller) *MockCurrencyResolver {
+	mock := &MockCurrencyResolver{ctrl: ctrl}
+	mock.recorder = &MockCurrencyResolverMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCurrencyResolver) EXPECT() *MockCurrencyResolverMockRecorder {
+	return m.recorder
+}
+
+// PairCurrency mocks base method.
+func (m *MockCurrencyResolver) PairCurrency(exchange, pair string) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PairCurrency", exchange, pair)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// PairCurrency indicates an expected call of PairCurrency.
+func (mr *MockCurrencyResolverMockRecorder) PairCurrency(exchange, pair any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PairCurrency", reflect.TypeOf((*MockCurrencyResolver)(nil).PairCurrency), exchange, pair)
+}
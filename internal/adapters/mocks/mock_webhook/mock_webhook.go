@@ -0,0 +1,56 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: webhook.go
+//
+// Generated by this command:
+//
+//	mockgen -source=webhook.go -destination=../mocks/mock_webhook/mock_webhook.go
+//
+
+// Package mock_webhook is a generated GoMock package.
+package mock_webhook
+
+import (
+	context "context"
+	models "crypto-alert-bot/internal/models"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockDeliveryRecorder is a mock of DeliveryRecorder interface.
+type MockDeliveryRecorder struct {
+	ctrl     *gomock.Controller
+	recorder *MockDeliveryRecorderMockRecorder
+	isgomock struct{}
+}
+
+// MockDeliveryRecorderMockRecorder is the mock recorder for MockDeliveryRecorder.
+type MockDeliveryRecorderMockRecorder struct {
+	mock *MockDeliveryRecorder
+}
+
+// NewMockDeliveryRecorder creates a new mock instance.
+func NewMockDeliveryRecorder(ctrl *gomock.Controller) *MockDeliveryRecorder {
+	mock := &MockDeliveryRecorder{ctrl: ctrl}
+	mock.recorder = &MockDeliveryRecorderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDeliveryRecorder) EXPECT() *MockDeliveryRecorderMockRecorder {
+	return m.recorder
+}
+
+// SaveDelivery mocks base method.
+func (m *MockDeliveryRecorder) SaveDelivery(arg0 context.Context, arg1 models.Delivery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveDelivery", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveDelivery indicates an expected call of SaveDelivery.
+func (mr *MockDeliveryRecorderMockRecorder) SaveDelivery(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveDelivery", reflect.TypeOf((*MockDeliveryRecorder)(nil).SaveDelivery), arg0, arg1)
+}
@@ -0,0 +1,256 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"github.com/pkg/errors"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// migrationsLockID is the advisory lock key that prevents two bots from migrating the same database at once
+const migrationsLockID = 7341823
+
+var migrationFileName = regexp.MustCompile(`^V(\d+)__(\w+)\.sql$`)
+
+var identifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Migration represents a versioned SQL migration script
+type Migration struct {
+	Version     int
+	Description string
+	SQL         string
+}
+
+// Render replaces the ${schema}, ${table_configs} and ${table_alerts} placeholders of the migration with the given names
+func (m Migration) Render(schema, tableConfigs, tableAlerts string) string {
+	return strings.NewReplacer(
+		"${schema}", schema,
+		"${table_configs}", tableConfigs,
+		"${table_alerts}", tableAlerts,
+	).Replace(m.SQL)
+}
+
+// Migrator applies the pending migrations and tracks the applied versions on the schema_migrations table.
+// The migrations are rendered with the configured schema and table names, the same ones the repository reads and writes
+type Migrator struct {
+	DB             *sql.DB
+	Migrations     []Migration
+	DbSchema       string
+	DbTableConfigs string
+	DbTableAlerts  string
+}
+
+// NewMigrator returns a new instance of Migrator with the migrations found on the given file system,
+// failing when the schema or table names aren't plain SQL identifiers
+func NewMigrator(db *sql.DB, fsys fs.FS, dbSchema, dbTableConfigs, dbTableAlerts string) (*Migrator, error) {
+	for _, name := range []string{dbSchema, dbTableConfigs, dbTableAlerts} {
+		if !identifier.MatchString(name) {
+			return nil, errors.Errorf("invalid schema or table name %q, expected letters, digits and underscores", name)
+		}
+	}
+
+	migrations, err := LoadMigrations(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{
+		DB:             db,
+		Migrations:     migrations,
+		DbSchema:       dbSchema,
+		DbTableConfigs: dbTableConfigs,
+		DbTableAlerts:  dbTableAlerts,
+	}, nil
+}
+
+// LoadMigrations reads the migration scripts from the file system, sorted by version
+func LoadMigrations(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, errors.Wrap(err, "error reading migrations")
+	}
+
+	var migrations []Migration
+	versions := make(map[int]string)
+
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".sql") {
+			continue
+		}
+
+		match := migrationFileName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, errors.Errorf("invalid migration file name %s, expected V<version>__<description>.sql", entry.Name())
+		}
+
+		version, err := strconv.Atoi(match[1])
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid migration version on %s", entry.Name())
+		}
+
+		if previous, ok := versions[version]; ok {
+			return nil, errors.Errorf("duplicated migration version %d on %s and %s", version, previous, entry.Name())
+		}
+		versions[version] = entry.Name()
+
+		content, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, errors.Wrapf(err, "error reading migration %s", entry.Name())
+		}
+
+		migrations = append(migrations, Migration{
+			Version:     version,
+			Description: strings.ReplaceAll(match[2], "_", " "),
+			SQL:         string(content),
+		})
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// Migrate applies every pending migration, each one on its own transaction, and returns the applied ones
+func (m *Migrator) Migrate(ctx context.Context) ([]Migration, error) {
+	conn, err := m.DB.Conn(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get db connection")
+	}
+	defer conn.Close()
+
+	_, err = conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", migrationsLockID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to acquire migrations lock")
+	}
+	defer conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", migrationsLockID)
+
+	applied, err := m.appliedVersions(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+
+	for _, migration := range m.Migrations {
+		if applied[migration.Version] {
+			continue
+		}
+
+		err = m.apply(ctx, conn, migration)
+		if err != nil {
+			return done, err
+		}
+
+		done = append(done, migration)
+	}
+
+	return done, nil
+}
+
+// Pending returns the migrations not yet applied to the database
+func (m *Migrator) Pending(ctx context.Context) ([]Migration, error) {
+	conn, err := m.DB.Conn(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get db connection")
+	}
+	defer conn.Close()
+
+	applied, err := m.appliedVersions(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []Migration
+
+	for _, migration := range m.Migrations {
+		if !applied[migration.Version] {
+			pending = append(pending, migration)
+		}
+	}
+
+	return pending, nil
+}
+
+// appliedVersions creates the tracking table if needed and returns the applied versions.
+// Databases previously migrated by Flyway are baselined from its history table on the first run
+func (m *Migrator) appliedVersions(ctx context.Context, conn *sql.Conn) (map[int]bool, error) {
+	_, err := conn.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS public.schema_migrations (
+		version INT PRIMARY KEY,
+		description TEXT NOT NULL,
+		applied_at TIMESTAMP NOT NULL DEFAULT now()
+	)`)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create schema_migrations table")
+	}
+
+	var hasFlywayHistory bool
+
+	err = conn.QueryRowContext(ctx, "SELECT to_regclass('public.flyway_schema_history') IS NOT NULL").Scan(&hasFlywayHistory)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to look up flyway history table")
+	}
+
+	if hasFlywayHistory {
+		_, err = conn.ExecContext(ctx, `INSERT INTO public.schema_migrations (version, description)
+			SELECT f.version::INT, f.description FROM public.flyway_schema_history f
+			WHERE f.success AND f.version ~ '^[0-9]+$'
+			AND NOT EXISTS (SELECT 1 FROM public.schema_migrations)
+			ON CONFLICT (version) DO NOTHING`)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to baseline from flyway history")
+		}
+	}
+
+	rows, err := conn.QueryContext(ctx, "SELECT version FROM public.schema_migrations")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query schema_migrations table")
+	}
+	defer rows.Close()
+
+	applied := make(map[int]bool)
+
+	for rows.Next() {
+		var version int
+
+		if err = rows.Scan(&version); err != nil {
+			return nil, errors.Wrap(err, "failed to scan migration version")
+		}
+
+		applied[version] = true
+	}
+
+	return applied, rows.Err()
+}
+
+// apply runs a single migration and records its version within the same transaction
+func (m *Migrator) apply(ctx context.Context, conn *sql.Conn, migration Migration) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to start transaction")
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, migration.Render(m.DbSchema, m.DbTableConfigs, m.DbTableAlerts))
+	if err != nil {
+		return errors.Wrapf(err, "failed to apply migration V%d (%s)", migration.Version, migration.Description)
+	}
+
+	_, err = tx.ExecContext(ctx, "INSERT INTO public.schema_migrations (version, description) VALUES ($1, $2)",
+		migration.Version, migration.Description)
+	if err != nil {
+		return errors.Wrapf(err, "failed to record migration V%d", migration.Version)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return errors.Wrap(err, "failed to commit transaction")
+	}
+
+	return nil
+}
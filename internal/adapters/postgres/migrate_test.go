@@ -0,0 +1,118 @@
+package postgres
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"crypto-alert-bot/migrations"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadMigrations(t *testing.T) {
+	tests := []struct {
+		name         string
+		files        fstest.MapFS
+		wantVersions []int
+		wantErr      bool
+		errContains  string
+	}{
+		{
+			name: "Sorted by version",
+			files: fstest.MapFS{
+				"V10__add_index.sql":          {Data: []byte("CREATE INDEX")},
+				"V2__add_column.sql":          {Data: []byte("ALTER TABLE")},
+				"V1__create_alerts_table.sql": {Data: []byte("CREATE TABLE")},
+				"migrations.go":               {Data: []byte("package migrations")},
+			},
+			wantVersions: []int{1, 2, 10},
+		},
+		{
+			name: "Invalid file name",
+			files: fstest.MapFS{
+				"create_alerts_table.sql": {Data: []byte("CREATE TABLE")},
+			},
+			wantErr:     true,
+			errContains: "invalid migration file name",
+		},
+		{
+			name: "Duplicated version",
+			files: fstest.MapFS{
+				"V1__create_alerts_table.sql": {Data: []byte("CREATE TABLE")},
+				"V1__create_configs.sql":      {Data: []byte("CREATE TABLE")},
+			},
+			wantErr:     true,
+			errContains: "duplicated migration version 1",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := LoadMigrations(tt.files)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errContains)
+				return
+			}
+
+			assert.NoError(t, err)
+
+			var versions []int
+			for _, migration := range got {
+				versions = append(versions, migration.Version)
+			}
+			assert.Equal(t, tt.wantVersions, versions)
+		})
+	}
+}
+
+func TestEmbeddedMigrations(t *testing.T) {
+	got, err := LoadMigrations(migrations.FS)
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, got)
+	assert.Equal(t, 1, got[0].Version)
+	assert.Equal(t, "create alerts table", got[0].Description)
+	assert.Contains(t, got[0].SQL, "CREATE TABLE ${schema}.${table_alerts}")
+
+	for _, migration := range got {
+		assert.NotContains(t, migration.SQL, "crypto_alerts", "V%d must use the schema placeholder", migration.Version)
+	}
+}
+
+func TestMigrationRender(t *testing.T) {
+	migration := Migration{SQL: "CREATE SCHEMA IF NOT EXISTS ${schema}; CREATE TABLE ${schema}.${table_alerts} (config_id INT REFERENCES ${schema}.${table_configs}(id));"}
+
+	got := migration.Render("bot_alerts", "bot_configs", "bot_history")
+
+	assert.Equal(t, "CREATE SCHEMA IF NOT EXISTS bot_alerts; CREATE TABLE bot_alerts.bot_history (config_id INT REFERENCES bot_alerts.bot_configs(id));", got)
+}
+
+func TestNewMigrator(t *testing.T) {
+	tests := []struct {
+		name         string
+		schema       string
+		tableConfigs string
+		tableAlerts  string
+		wantErr      bool
+	}{
+		{name: "Default names", schema: "crypto_alerts", tableConfigs: "configs", tableAlerts: "alerts"},
+		{name: "Custom schema", schema: "Desk_2", tableConfigs: "configs", tableAlerts: "alerts"},
+		{name: "Empty schema", schema: "", tableConfigs: "configs", tableAlerts: "alerts", wantErr: true},
+		{name: "Quoted table", schema: "crypto_alerts", tableConfigs: "configs", tableAlerts: `alerts"; DROP TABLE configs; --`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			migrator, err := NewMigrator(nil, migrations.FS, tt.schema, tt.tableConfigs, tt.tableAlerts)
+			if tt.wantErr {
+				assert.ErrorContains(t, err, "invalid schema or table name")
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.schema, migrator.DbSchema)
+		})
+	}
+}
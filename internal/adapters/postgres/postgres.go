@@ -3,8 +3,10 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"github.com/pkg/errors"
+	"strings"
 	"time"
 	"crypto-alert-bot/internal/models"
 )
@@ -35,18 +37,47 @@ func (p *Postgres) Save(ctx context.Context, timestamp time.Time, ticker *models
 	}
 	defer tx.Rollback()
 
-	configQuery := fmt.Sprintf("INSERT INTO %s.%s (refresh_rate, perc_oscillation) VALUES ($1, $2) RETURNING ID", p.DbSchema, p.DbTableConfigs)
+	configQuery := fmt.Sprintf("INSERT INTO %s.%s (refresh_rate, perc_oscillation, window_seconds, zscore) VALUES ($1, $2, $3, $4) RETURNING ID", p.DbSchema, p.DbTableConfigs)
 
 	var configID int
-	err = tx.QueryRowContext(ctx, configQuery, ticker.Config.RefreshRate, ticker.Config.PercOscillation).Scan(&configID)
+	err = tx.QueryRowContext(ctx, configQuery, ticker.Config.RefreshRate, ticker.Config.PercOscillation, int(ticker.Config.Window.Seconds()), ticker.Config.ZScore).Scan(&configID)
 	if err != nil {
 		return errors.Wrap(err, "failed to save ticker configs into configs table")
 	}
 
-	alertQuery := fmt.Sprintf("INSERT INTO %s.%s (pair, price_change, perc_change, final_price, config_id, timestamp) VALUES ($1, $2, $3, $4, $5, $6)",
-		p.DbSchema, p.DbTableAlerts)
+	alertQuery := fmt.Sprintf(`INSERT INTO %s.%s (pair, rule_type, target, expression, indicators, price_change, perc_change, final_price, ask, bid, price_source, direction, suppressed, config_id, timestamp)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)`, p.DbSchema, p.DbTableAlerts)
 
-	_, err = tx.ExecContext(ctx, alertQuery, ticker.Pair, ticker.AskPriceChange, ticker.AskPercChange, ticker.CurrentAsk, configID, timestamp)
+	ruleType := ticker.AlertType
+	if ruleType == "" {
+		ruleType = models.AlertThreshold
+	}
+
+	var target *float64
+	if ticker.Level != nil {
+		target = &ticker.Level.Target
+	} else if ticker.Peg != nil {
+		target = &ticker.Peg.Target
+	}
+
+	var expression *string
+	if source := ticker.RuleSource(); source != "" {
+		expression = &source
+	}
+
+	var indicators *string
+	if len(ticker.Indicators) > 0 {
+		data, err := json.Marshal(ticker.Indicators)
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal alert indicators")
+		}
+
+		values := string(data)
+		indicators = &values
+	}
+
+	_, err = tx.ExecContext(ctx, alertQuery, ticker.Pair, ruleType, target, expression, indicators, ticker.PriceChange, ticker.PercChange, ticker.Price(), ticker.CurrentAsk, ticker.CurrentBid, ticker.Config.PriceSource,
+		ticker.ChangeDirection(), ticker.Suppressed, configID, timestamp)
 	if err != nil {
 		return errors.Wrap(err, "failed to save ticker into alerts table")
 	}
@@ -58,3 +89,192 @@ func (p *Postgres) Save(ctx context.Context, timestamp time.Time, ticker *models
 
 	return nil
 }
+
+// SaveDelivery saves the outcome of a webhook delivery to the database
+func (p *Postgres) SaveDelivery(ctx context.Context, delivery models.Delivery) error {
+	query := fmt.Sprintf(`INSERT INTO %s.webhook_deliveries (endpoint, pair, alert_type, status, attempts, status_code, error, timestamp)
+		VALUES ($1, $2, $3, $4, $5, NULLIF($6, 0), NULLIF($7, ''), $8)`, p.DbSchema)
+
+	_, err := p.DB.ExecContext(ctx, query, delivery.Endpoint, delivery.Pair, delivery.AlertType, delivery.Status, delivery.Attempts,
+		delivery.StatusCode, delivery.Error, delivery.Timestamp)
+	if err != nil {
+		return errors.Wrap(err, "failed to save delivery into webhook deliveries table")
+	}
+
+	return nil
+}
+
+// alertColumns are the columns of an alert row, joined with its configs, in the order scanned by scanAlert
+const alertColumns = `a.id, a.pair, a.rule_type, COALESCE(a.target, 0), COALESCE(a.expression, ''), a.indicators, a.price_change, a.perc_change, a.final_price, COALESCE(a.ask, 0), COALESCE(a.bid, 0), a.price_source, COALESCE(a.direction, ''), a.suppressed, a.timestamp, c.refresh_rate, c.perc_oscillation, c.window_seconds, c.zscore`
+
+// QueryAlerts returns the page of the alerts matching the filter, newest first, along with the total count of matching alerts
+func (p *Postgres) QueryAlerts(ctx context.Context, filter models.AlertFilter, page models.Page) (models.AlertPage, error) {
+	if err := filter.Validate(); err != nil {
+		return models.AlertPage{}, errors.Wrap(err, "invalid alert filter")
+	}
+
+	page, err := page.Normalize()
+	if err != nil {
+		return models.AlertPage{}, errors.Wrap(err, "invalid alert page")
+	}
+
+	conditions, args := alertConditions(filter)
+	result := models.AlertPage{Page: page}
+
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM %s.%s a WHERE %s", p.DbSchema, p.DbTableAlerts, conditions)
+
+	if err = p.DB.QueryRowContext(ctx, countQuery, args...).Scan(&result.Total); err != nil {
+		return models.AlertPage{}, errors.Wrap(err, "failed to count alerts")
+	}
+
+	if result.Total <= page.Offset {
+		return result, nil
+	}
+
+	query := fmt.Sprintf(`SELECT %[1]s
+		FROM %[2]s.%[3]s a JOIN %[2]s.%[4]s c ON c.id = a.config_id
+		WHERE %[5]s
+		ORDER BY a.timestamp DESC, a.id DESC
+		LIMIT $%[6]d OFFSET $%[7]d`, alertColumns, p.DbSchema, p.DbTableAlerts, p.DbTableConfigs, conditions, len(args)+1, len(args)+2)
+
+	rows, err := p.DB.QueryContext(ctx, query, append(args, page.Limit, page.Offset)...)
+	if err != nil {
+		return models.AlertPage{}, errors.Wrap(err, "failed to query alerts table")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		alert, err := scanAlert(rows)
+		if err != nil {
+			return models.AlertPage{}, err
+		}
+
+		result.Alerts = append(result.Alerts, alert)
+	}
+
+	if err = rows.Err(); err != nil {
+		return models.AlertPage{}, errors.Wrap(err, "failed to iterate alert rows")
+	}
+
+	return result, nil
+}
+
+// AggregateAlerts counts the alerts matching the filter per pair and bucket, along with their largest percentage change,
+// newest bucket first
+func (p *Postgres) AggregateAlerts(ctx context.Context, filter models.AlertFilter, size models.BucketSize) ([]models.AlertBucket, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, errors.Wrap(err, "invalid alert filter")
+	}
+
+	query, args, err := p.aggregateQuery(filter, size)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := p.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to aggregate alerts table")
+	}
+	defer rows.Close()
+
+	var buckets []models.AlertBucket
+
+	for rows.Next() {
+		var bucket models.AlertBucket
+
+		if err = rows.Scan(&bucket.Pair, &bucket.Start, &bucket.Count, &bucket.MaxPercChange); err != nil {
+			return nil, errors.Wrap(err, "failed to scan alert bucket row")
+		}
+
+		buckets = append(buckets, bucket)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to iterate alert bucket rows")
+	}
+
+	return buckets, nil
+}
+
+// aggregateQuery builds the query aggregating the alerts matching the filter per pair and bucket
+func (p *Postgres) aggregateQuery(filter models.AlertFilter, size models.BucketSize) (string, []any, error) {
+	switch size {
+	case models.BucketHour, models.BucketDay:
+	default:
+		return "", nil, errors.Errorf("unknown bucket size %q", size)
+	}
+
+	conditions, args := alertConditions(filter)
+
+	query := fmt.Sprintf(`SELECT a.pair, date_trunc('%[1]s', a.timestamp) AS bucket, COUNT(*), MAX(ABS(a.perc_change))
+		FROM %[2]s.%[3]s a
+		WHERE %[4]s
+		GROUP BY a.pair, bucket
+		ORDER BY bucket DESC, a.pair`, size, p.DbSchema, p.DbTableAlerts, conditions)
+
+	return query, args, nil
+}
+
+// alertConditions builds the WHERE conditions selecting the alerts matching the filter, along with their arguments
+func alertConditions(filter models.AlertFilter) (string, []any) {
+	var conditions []string
+	var args []any
+
+	add := func(condition string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.Pair != "" {
+		add("a.pair = $%d", filter.Pair)
+	}
+
+	if filter.RuleType != "" {
+		add("a.rule_type = $%d", filter.RuleType)
+	}
+
+	if filter.Direction != "" {
+		add("COALESCE(a.direction, '') = $%d", filter.Direction)
+	}
+
+	if !filter.From.IsZero() {
+		add("a.timestamp >= $%d", filter.From.UTC())
+	}
+
+	if !filter.To.IsZero() {
+		add("a.timestamp < $%d", filter.To.UTC())
+	}
+
+	if filter.MinPercChange > 0 {
+		add("ABS(a.perc_change) >= $%d", filter.MinPercChange)
+	}
+
+	if len(conditions) == 0 {
+		return "TRUE", nil
+	}
+
+	return strings.Join(conditions, " AND "), args
+}
+
+// scanAlert scans an alert row selected with alertColumns
+func scanAlert(rows *sql.Rows) (models.Alert, error) {
+	var alert models.Alert
+	var windowSeconds int
+	var indicators []byte
+
+	err := rows.Scan(&alert.ID, &alert.Pair, &alert.RuleType, &alert.Target, &alert.Expression, &indicators, &alert.PriceChange, &alert.PercChange, &alert.FinalPrice, &alert.Ask, &alert.Bid, &alert.PriceSource, &alert.Direction, &alert.Suppressed, &alert.Timestamp,
+		&alert.Config.RefreshRate, &alert.Config.PercOscillation, &windowSeconds, &alert.Config.ZScore)
+	if err != nil {
+		return models.Alert{}, errors.Wrap(err, "failed to scan alert row")
+	}
+
+	alert.Config.Window = time.Duration(windowSeconds) * time.Second
+
+	if indicators != nil {
+		if err = json.Unmarshal(indicators, &alert.Indicators); err != nil {
+			return models.Alert{}, errors.Wrap(err, "failed to unmarshal alert indicators")
+		}
+	}
+
+	return alert, nil
+}
@@ -0,0 +1,61 @@
+package postgres
+
+import (
+	"testing"
+	"time"
+
+	"crypto-alert-bot/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAlertConditions(t *testing.T) {
+	from := time.Date(2024, 5, 1, 2, 0, 0, 0, time.FixedZone("CEST", 2*60*60))
+	to := time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		filter         models.AlertFilter
+		wantConditions string
+		wantArgs       []any
+	}{
+		{
+			name:           "No filter",
+			wantConditions: "TRUE",
+		},
+		{
+			name:           "Pair and rule type",
+			filter:         models.AlertFilter{Pair: "BTCUSD", RuleType: models.AlertLevel},
+			wantConditions: "a.pair = $1 AND a.rule_type = $2",
+			wantArgs:       []any{"BTCUSD", models.AlertLevel},
+		},
+		{
+			name:           "Every filter",
+			filter:         models.AlertFilter{Pair: "ETHUSD", Direction: models.DirectionDown, From: from, To: to, MinPercChange: 3},
+			wantConditions: "a.pair = $1 AND COALESCE(a.direction, '') = $2 AND a.timestamp >= $3 AND a.timestamp < $4 AND ABS(a.perc_change) >= $5",
+			wantArgs:       []any{"ETHUSD", models.DirectionDown, from.UTC(), to, 3.0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conditions, args := alertConditions(tt.filter)
+
+			assert.Equal(t, tt.wantConditions, conditions)
+			assert.Equal(t, tt.wantArgs, args)
+		})
+	}
+}
+
+func TestAggregateQuery(t *testing.T) {
+	repo := NewPostgres(nil, "crypto_alerts", "configs", "alerts")
+
+	query, args, err := repo.aggregateQuery(models.AlertFilter{Pair: "BTCUSD"}, models.BucketHour)
+	assert.NoError(t, err)
+	assert.Contains(t, query, "date_trunc('hour', a.timestamp)")
+	assert.Contains(t, query, "FROM crypto_alerts.alerts a")
+	assert.Contains(t, query, "WHERE a.pair = $1")
+	assert.Equal(t, []any{"BTCUSD"}, args)
+
+	_, _, err = repo.aggregateQuery(models.AlertFilter{}, "minute'); DROP TABLE alerts; --")
+	assert.ErrorContains(t, err, "unknown bucket size")
+}
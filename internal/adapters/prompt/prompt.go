@@ -15,17 +15,30 @@ type ApiDataValidator interface {
 	IsPairValid(pair string) (bool, error)
 }
 
-// AskUserInput prompts the user for various inputs and returns a slice of ticker structs
-func AskUserInput(validator ApiDataValidator) *models.Tickers {
+// CurrencyResolver is implemented by the validators knowing the currency the pairs they validated are quoted in
+type CurrencyResolver interface {
+	PairCurrency(pair string) string
+}
+
+// AskUserInput prompts the user for various inputs and returns a slice of ticker structs within the rate limits
+func AskUserInput(validator ApiDataValidator, limits models.RateLimits) *models.Tickers {
 	var tickers models.Tickers
 
 	for {
 		pair := promptPair(validator)
 		refreshRate := promptRefreshRate()
 		percThreshold := promptPercThreshold()
+		direction := promptDirection()
+		priceSource := promptPriceSource()
 		lifetime := promptLifetime()
 
 		ticker := models.NewTicker(pair, refreshRate, percThreshold, lifetime)
+		ticker.Config.Direction = direction
+		ticker.Config.PriceSource = priceSource
+
+		if resolver, ok := validator.(CurrencyResolver); ok {
+			ticker.Currency = resolver.PairCurrency(pair)
+		}
 
 		tickers = append(tickers, ticker)
 
@@ -35,7 +48,7 @@ func AskUserInput(validator ApiDataValidator) *models.Tickers {
 			continue
 		}
 
-		if tickers.IsAboveRateLimit() {
+		if tickers.IsAboveRateLimit(limits) {
 			fmt.Println("The current bot configuration would exceed the rate limit. Please adjust bot configurations")
 			continue
 		}
@@ -109,6 +122,46 @@ func promptPercThreshold() float64 {
 	}
 }
 
+// promptDirection prompts the user to choose the direction of the price changes to alert on
+func promptDirection() models.Direction {
+	reader := bufio.NewReader(os.Stdin)
+
+	for {
+		fmt.Print("Alert on price rises, drops or both (up, down, both) or just hit enter for both: ")
+
+		input, _ := reader.ReadString('\n')
+
+		direction, err := models.ParseDirection(input)
+
+		if err != nil {
+			fmt.Println("Invalid choice. Please enter up, down or both")
+			continue
+		}
+
+		return direction
+	}
+}
+
+// promptPriceSource prompts the user to choose the price the changes are evaluated on, the last trade price isn't provided by Uphold
+func promptPriceSource() models.PriceSource {
+	reader := bufio.NewReader(os.Stdin)
+
+	for {
+		fmt.Print("Evaluate the ask, bid or mid price (ask, bid, mid) or just hit enter for ask: ")
+
+		input, _ := reader.ReadString('\n')
+
+		priceSource, err := models.ParsePriceSource(input)
+
+		if err != nil || priceSource == models.PriceLast {
+			fmt.Println("Invalid choice. Please enter ask, bid or mid")
+			continue
+		}
+
+		return priceSource
+	}
+}
+
 // promptLifetime prompts the user to choose a lifetime for the ticker bot
 func promptLifetime() time.Duration {
 	reader := bufio.NewReader(os.Stdin)
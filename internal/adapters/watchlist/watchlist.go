@@ -0,0 +1,730 @@
+package watchlist
+
+import (
+	"bytes"
+	"crypto-alert-bot/internal/models"
+	"encoding/json"
+	"fmt"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=../mocks/mock_watchlist/mock_$GOFILE
+type ApiDataValidator interface {
+	IsExchangePairValid(exchange, pair string) (bool, error)
+	HasLastPrice(exchange string) bool
+}
+
+// CurrencyResolver is implemented by the validators knowing the currency the pairs they validated are quoted in
+type CurrencyResolver interface {
+	PairCurrency(exchange, pair string) string
+}
+
+// LogChannel is the builtin channel that writes the alerts to the log
+const LogChannel = "log"
+
+// WebhooksChannel is the builtin channel that posts the alerts to the webhooks given with the -webhook flag
+const WebhooksChannel = "webhooks"
+
+// channelTypes lists the supported alert channel types
+var channelTypes = map[string]bool{
+	"webhook":  true,
+	"slack":    true,
+	"discord":  true,
+	"telegram": true,
+	"email":    true,
+}
+
+// File represents the content of a watchlist file
+type File struct {
+	Channels  map[string]Channel `yaml:"channels" json:"channels"`
+	Tickers   []Entry            `yaml:"tickers" json:"tickers"`
+	Arbitrage []Arbitrage        `yaml:"arbitrage" json:"arbitrage"`
+}
+
+// Arbitrage represents a group of watched pairs quoting the same asset, alerted when their prices diverge by more than
+// the threshold percentage after the fee percentage. Quotes older than max_age are ignored, twice the longest leg refresh rate by default
+type Arbitrage struct {
+	Legs      []models.ArbitrageLeg `yaml:"legs" json:"legs"`
+	Threshold float64               `yaml:"threshold" json:"threshold"`
+	Fee       float64               `yaml:"fee" json:"fee"`
+	MaxAge    string                `yaml:"max_age" json:"max_age"`
+}
+
+// Channel represents an alert channel the tickers can be routed to
+type Channel struct {
+	Type      string `yaml:"type" json:"type"`
+	URL       string `yaml:"url" json:"url"`
+	SecretEnv string `yaml:"secret_env" json:"secret_env"`
+	TokenEnv  string `yaml:"token_env" json:"token_env"`
+	ChatID    string `yaml:"chat_id" json:"chat_id"`
+
+	SMTPAddr    string   `yaml:"smtp_addr" json:"smtp_addr"`
+	StartTLS    bool     `yaml:"starttls" json:"starttls"`
+	Username    string   `yaml:"username" json:"username"`
+	PasswordEnv string   `yaml:"password_env" json:"password_env"`
+	From        string   `yaml:"from" json:"from"`
+	To          []string `yaml:"to" json:"to"`
+	Digest      int      `yaml:"digest" json:"digest"`
+}
+
+// Entry represents a single ticker configuration on the watchlist file
+type Entry struct {
+	Pair          string      `yaml:"pair" json:"pair"`
+	Exchange      string      `yaml:"exchange" json:"exchange"`
+	RefreshRate   *float64    `yaml:"refresh_rate" json:"refresh_rate"`
+	Threshold     *float64    `yaml:"threshold" json:"threshold"`
+	ThresholdUp   *float64    `yaml:"threshold_up" json:"threshold_up"`
+	ThresholdDown *float64    `yaml:"threshold_down" json:"threshold_down"`
+	Direction     string      `yaml:"direction" json:"direction"`
+	Levels        []Level     `yaml:"levels" json:"levels"`
+	PriceSource   string      `yaml:"price_source" json:"price_source"`
+	Window        string      `yaml:"window" json:"window"`
+	Baseline      string      `yaml:"window_baseline" json:"window_baseline"`
+	ZScore        *float64    `yaml:"zscore" json:"zscore"`
+	Volatility    string      `yaml:"volatility_window" json:"volatility_window"`
+	Cooldown      string      `yaml:"cooldown" json:"cooldown"`
+	Rearm         *float64    `yaml:"rearm" json:"rearm"`
+	StaleAfter    string      `yaml:"stale_after" json:"stale_after"`
+	Rules         []string    `yaml:"rules" json:"rules"`
+	Spread        *Spread     `yaml:"spread" json:"spread"`
+	Indicators    *Indicators `yaml:"indicators" json:"indicators"`
+	Peg           *Peg        `yaml:"peg" json:"peg"`
+	Lifetime      int         `yaml:"lifetime" json:"lifetime"`
+	Stream        bool        `yaml:"stream" json:"stream"`
+	Channels      []string    `yaml:"channels" json:"channels"`
+}
+
+// Level represents a price level alerted when the price crosses above or below it
+type Level struct {
+	Above *float64 `yaml:"above" json:"above"`
+	Below *float64 `yaml:"below" json:"below"`
+}
+
+// Spread represents the bid/ask spread limits of a watchlist entry, in percentage of the mid price or absolute,
+// alerted once the spread stayed beyond them for the duration
+type Spread struct {
+	MaxPct *float64 `yaml:"max_pct" json:"max_pct"`
+	Max    *float64 `yaml:"max" json:"max"`
+	For    string   `yaml:"for" json:"for"`
+}
+
+// validate returns every problem found on the spread limits
+func (s Spread) validate() []error {
+	var errs []error
+
+	if s.MaxPct == nil && s.Max == nil {
+		errs = append(errs, errors.New("spread must set max_pct or max"))
+	}
+
+	if s.MaxPct != nil && *s.MaxPct <= 0 {
+		errs = append(errs, errors.Errorf("spread max_pct must be positive, got %v", *s.MaxPct))
+	}
+
+	if s.Max != nil && *s.Max <= 0 {
+		errs = append(errs, errors.Errorf("spread max must be positive, got %v", *s.Max))
+	}
+
+	if _, err := parseDuration("spread for", s.For); err != nil {
+		errs = append(errs, err)
+	}
+
+	return errs
+}
+
+// Indicators represents the indicator rules of a watchlist entry, computed over bars of the interval,
+// or over every price when not given
+type Indicators struct {
+	Interval string   `yaml:"interval" json:"interval"`
+	Rules    []string `yaml:"rules" json:"rules"`
+}
+
+// validate returns every problem found on the indicators besides their rules, checked with the other entry rules
+func (i Indicators) validate() []error {
+	var errs []error
+
+	if len(i.Rules) == 0 {
+		errs = append(errs, errors.New("indicators must set rules"))
+	}
+
+	if _, err := parseDuration("indicators interval", i.Interval); err != nil {
+		errs = append(errs, err)
+	}
+
+	return errs
+}
+
+// Peg represents the peg of a stablecoin entry, alerted once the price stayed outside the tolerance band,
+// a percentage of the target, for the duration and again when it re-enters the band
+type Peg struct {
+	Target    *float64 `yaml:"target" json:"target"`
+	Tolerance *float64 `yaml:"tolerance_pct" json:"tolerance_pct"`
+	For       string   `yaml:"for" json:"for"`
+}
+
+// validate returns every problem found on the peg
+func (p Peg) validate() []error {
+	var errs []error
+
+	if p.Target == nil {
+		errs = append(errs, errors.New("peg target is required"))
+	} else if *p.Target <= 0 {
+		errs = append(errs, errors.Errorf("peg target must be positive, got %v", *p.Target))
+	}
+
+	if p.Tolerance == nil {
+		errs = append(errs, errors.New("peg tolerance_pct is required"))
+	} else if *p.Tolerance <= 0 {
+		errs = append(errs, errors.Errorf("peg tolerance_pct must be positive, got %v", *p.Tolerance))
+	}
+
+	if _, err := parseDuration("peg for", p.For); err != nil {
+		errs = append(errs, err)
+	}
+
+	return errs
+}
+
+// EntryError describes why a watchlist entry was rejected
+type EntryError struct {
+	Index int
+	Pair  string
+	Err   error
+}
+
+func (e *EntryError) Error() string {
+	if e.Pair == "" {
+		return fmt.Sprintf("entry %d: %v", e.Index+1, e.Err)
+	}
+
+	return fmt.Sprintf("entry %d (%s): %v", e.Index+1, e.Pair, e.Err)
+}
+
+// ValidationError holds every problem found while validating a watchlist
+type ValidationError struct {
+	Entries []*EntryError
+}
+
+func (e *ValidationError) Error() string {
+	lines := make([]string, 0, len(e.Entries)+1)
+	lines = append(lines, fmt.Sprintf("invalid watchlist, %d problem(s) found:", len(e.Entries)))
+
+	for _, entryErr := range e.Entries {
+		lines = append(lines, "  - "+entryErr.Error())
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// Load reads a YAML or JSON watchlist file and returns the tickers validated against the rate limits
+func Load(path string, validator ApiDataValidator, limits models.RateLimits) (*models.Tickers, error) {
+	file, err := LoadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return file.Build(validator, limits)
+}
+
+// LoadFile reads and decodes a YAML or JSON watchlist file without validating it
+func LoadFile(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "error reading watchlist file")
+	}
+
+	return Parse(data, filepath.Ext(path))
+}
+
+// Parse decodes the watchlist content according to the file extension
+func Parse(data []byte, ext string) (*File, error) {
+	var file File
+
+	switch strings.ToLower(ext) {
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+
+		if err := decoder.Decode(&file); err != nil {
+			return nil, errors.Wrap(err, "error decoding yaml watchlist")
+		}
+	case ".json":
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+
+		if err := decoder.Decode(&file); err != nil {
+			return nil, errors.Wrap(err, "error decoding json watchlist")
+		}
+	default:
+		return nil, errors.Errorf("unsupported watchlist format %q, use .yaml, .yml or .json", ext)
+	}
+
+	return &file, nil
+}
+
+// Build validates every entry and builds the tickers, reporting all invalid entries at once.
+// The calls per minute the tickers need are checked against the rate limits of each exchange, once the quote currencies
+// known to the validator are filled in so compact pairs are batched as they will be at runtime
+func (f *File) Build(validator ApiDataValidator, limits models.RateLimits) (*models.Tickers, error) {
+	if len(f.Tickers) == 0 {
+		return nil, errors.New("watchlist has no tickers")
+	}
+
+	if err := f.validateChannels(); err != nil {
+		return nil, err
+	}
+
+	var tickers models.Tickers
+	var problems []*EntryError
+
+	for i, entry := range f.Tickers {
+		pair := strings.ToUpper(strings.TrimSpace(entry.Pair))
+		exchange := strings.ToLower(strings.TrimSpace(entry.Exchange))
+
+		errs := entry.validate(exchange, pair, validator)
+		errs = append(errs, f.validateRoutes(entry.Channels)...)
+		for _, err := range errs {
+			problems = append(problems, &EntryError{Index: i, Pair: pair, Err: err})
+		}
+
+		if len(errs) > 0 {
+			continue
+		}
+
+		direction, _ := entry.direction()
+		priceSource, _ := models.ParsePriceSource(entry.PriceSource)
+		window, _ := parseDuration("window", entry.Window)
+		cooldown, _ := parseDuration("cooldown", entry.Cooldown)
+		staleAfter, _ := parseDuration("stale_after", entry.StaleAfter)
+		volatility, _ := parseDuration("volatility_window", entry.Volatility)
+		baseline, _ := models.ParseWindowBaseline(entry.Baseline)
+
+		ticker := models.NewTicker(pair, *entry.RefreshRate, entry.threshold(direction), time.Duration(entry.Lifetime))
+		ticker.Exchange = exchange
+		ticker.Config.Direction = direction
+		ticker.Config.PriceSource = priceSource
+		ticker.Config.PercOscillationUp = valueOrZero(entry.ThresholdUp)
+		ticker.Config.PercOscillationDown = valueOrZero(entry.ThresholdDown)
+		ticker.Config.Levels = entry.priceLevels()
+		ticker.Config.Window = window
+		ticker.Config.WindowBaseline = baseline
+		ticker.Config.ZScore = valueOrZero(entry.ZScore)
+		ticker.Config.Cooldown = cooldown
+		ticker.Config.Rearm = valueOrZero(entry.Rearm)
+		ticker.Config.StaleAfter = staleAfter
+		ticker.Config.Rules, _ = entry.rules()
+		ticker.Config.IndicatorInterval = entry.indicatorInterval()
+		ticker.Config.Stream = entry.Stream
+		ticker.Config.Channels = entry.Channels
+
+		if resolver, ok := validator.(CurrencyResolver); ok {
+			ticker.Currency = resolver.PairCurrency(exchange, pair)
+		}
+
+		if volatility > 0 {
+			ticker.Config.VolatilityWindow = volatility
+		}
+
+		tickers = append(tickers, ticker)
+	}
+
+	if len(problems) > 0 {
+		return nil, &ValidationError{Entries: problems}
+	}
+
+	if err := f.linkArbitrage(tickers); err != nil {
+		return nil, err
+	}
+
+	if tickers.IsAboveRateLimit(limits) {
+		return nil, errors.New("the watchlist configuration would exceed the rate limit, please increase refresh rates or remove pairs")
+	}
+
+	return &tickers, nil
+}
+
+// validateChannels checks that every channel has a supported type and the settings it needs
+func (f *File) validateChannels() error {
+	for name, channel := range f.Channels {
+		if name == LogChannel || name == WebhooksChannel {
+			return errors.Errorf("channel %q is builtin and can't be redefined", name)
+		}
+
+		if !channelTypes[channel.Type] {
+			return errors.Errorf("channel %q has unsupported type %q", name, channel.Type)
+		}
+
+		switch channel.Type {
+		case "telegram":
+			if channel.TokenEnv == "" || channel.ChatID == "" {
+				return errors.Errorf("channel %q: token_env and chat_id are required", name)
+			}
+		case "email":
+			if channel.SMTPAddr == "" || channel.From == "" || len(channel.To) == 0 {
+				return errors.Errorf("channel %q: smtp_addr, from and to are required", name)
+			}
+
+			if channel.Digest < 0 {
+				return errors.Errorf("channel %q: digest can't be negative, got %d", name, channel.Digest)
+			}
+		default:
+			if channel.URL == "" {
+				return errors.Errorf("channel %q: url is required", name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// linkArbitrage adds the arbitrage rule of each group to the tickers of its legs, sharing their quotes on a single quote board
+func (f *File) linkArbitrage(tickers models.Tickers) error {
+	if len(f.Arbitrage) == 0 {
+		return nil
+	}
+
+	board := models.NewQuoteBoard()
+
+	for i, group := range f.Arbitrage {
+		if len(group.Legs) < 2 {
+			return errors.Errorf("arbitrage %d needs at least 2 legs", i+1)
+		}
+
+		if group.Threshold <= 0 {
+			return errors.Errorf("arbitrage %d threshold must be positive, got %v", i+1, group.Threshold)
+		}
+
+		if group.Fee < 0 {
+			return errors.Errorf("arbitrage %d fee can't be negative, got %v", i+1, group.Fee)
+		}
+
+		maxAge, err := parseDuration("max_age", group.MaxAge)
+		if err != nil {
+			return errors.Wrapf(err, "arbitrage %d", i+1)
+		}
+
+		rule := &models.ArbitrageRule{Board: board, Threshold: group.Threshold, Fee: group.Fee}
+		var legTickers models.Tickers
+
+		for _, leg := range group.Legs {
+			ticker := findTicker(tickers, leg)
+			if ticker == nil {
+				return errors.Errorf("arbitrage %d leg %s is not on the watchlist", i+1, leg)
+			}
+
+			if slices.Contains(legTickers, ticker) {
+				return errors.Errorf("arbitrage %d leg %s is listed twice", i+1, leg)
+			}
+
+			if len(legTickers) > 0 && !samePair(legTickers[0].Pair, ticker.Pair) {
+				return errors.Errorf("arbitrage %d legs must quote the same pair, got %s and %s, write them in the BASE-QUOTE notation (e.g. BTC-USD)",
+					i+1, rule.Legs[0], leg)
+			}
+
+			rule.Legs = append(rule.Legs, models.ArbitrageLeg{Exchange: ticker.ExchangeName(), Pair: ticker.Pair})
+			legTickers = append(legTickers, ticker)
+
+			if group.MaxAge == "" {
+				maxAge = max(maxAge, 2*time.Duration(ticker.Config.RefreshRate*float64(time.Second)))
+			}
+		}
+
+		rule.MaxAge = maxAge
+
+		for _, ticker := range legTickers {
+			ticker.Board = board
+			ticker.Config.Rules = append(ticker.Config.AlertRules(), rule)
+		}
+	}
+
+	return nil
+}
+
+// samePair checks if two pairs quote the same asset in the same currency, comparing the base and quote of the BASE-QUOTE
+// notation, or the whole pair when any of them is written in an exchange notation
+func samePair(a, b string) bool {
+	baseA, quoteA, errA := models.ParsePair(a)
+	baseB, quoteB, errB := models.ParsePair(b)
+
+	if errA != nil || errB != nil {
+		return models.CompactPair(a) == models.CompactPair(b)
+	}
+
+	return baseA == baseB && quoteA == quoteB
+}
+
+// findTicker returns the ticker of the arbitrage leg, nil when it's not on the watchlist
+func findTicker(tickers models.Tickers, leg models.ArbitrageLeg) *models.Ticker {
+	exchange := strings.ToLower(strings.TrimSpace(leg.Exchange))
+	if exchange == "" {
+		exchange = models.DefaultExchange
+	}
+
+	for _, ticker := range tickers {
+		if ticker.ExchangeName() == exchange && models.CompactPair(ticker.Pair) == models.CompactPair(leg.Pair) {
+			return ticker
+		}
+	}
+
+	return nil
+}
+
+// validateRoutes returns a problem for every channel the entry is routed to that is not defined
+func (f *File) validateRoutes(channels []string) []error {
+	var errs []error
+
+	for _, name := range channels {
+		if _, ok := f.Channels[name]; !ok && name != LogChannel && name != WebhooksChannel {
+			errs = append(errs, errors.Errorf("channel %q is not defined", name))
+		}
+	}
+
+	return errs
+}
+
+// validate returns every problem found on the entry
+func (e Entry) validate(exchange, pair string, validator ApiDataValidator) []error {
+	var errs []error
+
+	if pair == "" {
+		errs = append(errs, errors.New("pair is required"))
+	} else if isValid, err := validator.IsExchangePairValid(exchange, pair); !isValid {
+		if err == nil {
+			err = errors.New("pair is not valid")
+		}
+		errs = append(errs, err)
+	}
+
+	if e.RefreshRate == nil {
+		errs = append(errs, errors.New("refresh_rate is required"))
+	} else if *e.RefreshRate <= 0 {
+		errs = append(errs, errors.Errorf("refresh_rate must be positive, got %v", *e.RefreshRate))
+	}
+
+	direction, err := e.direction()
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	for i, level := range e.Levels {
+		switch {
+		case (level.Above == nil) == (level.Below == nil):
+			errs = append(errs, errors.Errorf("level %d must set either above or below", i+1))
+		case level.Above != nil && *level.Above <= 0:
+			errs = append(errs, errors.Errorf("level %d must be positive, got %v", i+1, *level.Above))
+		case level.Below != nil && *level.Below <= 0:
+			errs = append(errs, errors.Errorf("level %d must be positive, got %v", i+1, *level.Below))
+		}
+	}
+
+	priceSource, err := models.ParsePriceSource(e.PriceSource)
+	if err != nil {
+		errs = append(errs, err)
+	} else if priceSource == models.PriceLast && !validator.HasLastPrice(exchange) {
+		if exchange == "" {
+			exchange = models.DefaultExchange
+		}
+
+		errs = append(errs, errors.Errorf("price_source last is not provided by %s", exchange))
+	}
+
+	if window, err := parseDuration("window", e.Window); err != nil {
+		errs = append(errs, err)
+	} else if window > 0 && e.RefreshRate != nil && window.Seconds() < *e.RefreshRate {
+		errs = append(errs, errors.Errorf("window %v is shorter than refresh_rate %vs", window, *e.RefreshRate))
+	}
+
+	if _, err := models.ParseWindowBaseline(e.Baseline); err != nil {
+		errs = append(errs, err)
+	}
+
+	if e.ZScore != nil && *e.ZScore <= 0 {
+		errs = append(errs, errors.Errorf("zscore must be positive, got %v", *e.ZScore))
+	}
+
+	if volatility, err := parseDuration("volatility_window", e.Volatility); err != nil {
+		errs = append(errs, err)
+	} else if e.ZScore != nil && e.RefreshRate != nil {
+		if volatility == 0 {
+			volatility = models.DefaultVolatilityWindow
+		}
+
+		if volatility.Seconds() < *e.RefreshRate*models.MinVolatilityReturns {
+			errs = append(errs, errors.Errorf("volatility_window %v holds fewer than %d prices at refresh_rate %vs", volatility, models.MinVolatilityReturns, *e.RefreshRate))
+		}
+	}
+
+	if _, err := parseDuration("cooldown", e.Cooldown); err != nil {
+		errs = append(errs, err)
+	}
+
+	if e.Rearm != nil && *e.Rearm <= 0 {
+		errs = append(errs, errors.Errorf("rearm must be positive, got %v", *e.Rearm))
+	}
+
+	if staleAfter, err := parseDuration("stale_after", e.StaleAfter); err != nil {
+		errs = append(errs, err)
+	} else if staleAfter > 0 && e.RefreshRate != nil && staleAfter.Seconds() <= *e.RefreshRate {
+		errs = append(errs, errors.Errorf("stale_after %v must be longer than refresh_rate %vs", staleAfter, *e.RefreshRate))
+	}
+
+	if _, err := e.rules(); err != nil {
+		errs = append(errs, err)
+	}
+
+	if e.Spread != nil {
+		errs = append(errs, e.Spread.validate()...)
+	}
+
+	if e.Indicators != nil {
+		errs = append(errs, e.Indicators.validate()...)
+	}
+
+	if e.Peg != nil {
+		errs = append(errs, e.Peg.validate()...)
+	}
+
+	upMissing := direction.Allows(models.DirectionUp) && e.ThresholdUp == nil
+	downMissing := direction.Allows(models.DirectionDown) && e.ThresholdDown == nil
+
+	if e.Threshold == nil && e.ZScore == nil && (upMissing || downMissing) {
+		errs = append(errs, errors.New("threshold is required"))
+	} else if e.Threshold != nil && *e.Threshold < 0 {
+		errs = append(errs, errors.Errorf("threshold can't be negative, got %v", *e.Threshold))
+	}
+
+	if e.ThresholdUp != nil && *e.ThresholdUp <= 0 {
+		errs = append(errs, errors.Errorf("threshold_up must be positive, got %v", *e.ThresholdUp))
+	}
+
+	if e.ThresholdDown != nil && *e.ThresholdDown <= 0 {
+		errs = append(errs, errors.Errorf("threshold_down must be positive, got %v", *e.ThresholdDown))
+	}
+
+	if e.Lifetime < 0 {
+		errs = append(errs, errors.Errorf("lifetime can't be negative, got %d", e.Lifetime))
+	}
+
+	return errs
+}
+
+// direction returns the direction the entry alerts price changes on,
+// entries with levels, rules, spread limits, indicators or a peg and no thresholds only alert on those
+func (e Entry) direction() (models.Direction, error) {
+	noThresholds := e.Threshold == nil && e.ThresholdUp == nil && e.ThresholdDown == nil
+
+	if e.Direction == "" && (len(e.Levels) > 0 || len(e.Rules) > 0 || e.Spread != nil || e.Indicators != nil || e.Peg != nil) && noThresholds {
+		return models.DirectionNone, nil
+	}
+
+	return models.ParseDirection(e.Direction)
+}
+
+// parseDuration parses the duration of the entry field, 0 when not given
+func parseDuration(field, value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, errors.Errorf("invalid %s %q, use a duration such as 15m", field, value)
+	}
+
+	if duration <= 0 {
+		return 0, errors.Errorf("%s must be positive, got %v", field, duration)
+	}
+
+	return duration, nil
+}
+
+// rules returns the builtin threshold rule, the spread rule when limits are given, and the compiled rule expressions of the entry
+func (e Entry) rules() ([]models.Rule, error) {
+	rules := []models.Rule{models.ThresholdRule{}}
+
+	if e.Spread != nil {
+		spreadFor, _ := parseDuration("spread for", e.Spread.For)
+		rules = append(rules, &models.SpreadRule{MaxPct: valueOrZero(e.Spread.MaxPct), Max: valueOrZero(e.Spread.Max), For: spreadFor})
+	}
+
+	if e.Peg != nil {
+		pegFor, _ := parseDuration("peg for", e.Peg.For)
+		rules = append(rules, &models.PegRule{Target: valueOrZero(e.Peg.Target), Tolerance: valueOrZero(e.Peg.Tolerance), For: pegFor})
+	}
+
+	for i, source := range e.Rules {
+		rule, err := models.NewExpressionRule(source)
+		if err != nil {
+			return nil, errors.Wrapf(err, "rule %d", i+1)
+		}
+
+		rules = append(rules, rule)
+	}
+
+	if e.Indicators != nil {
+		for i, source := range e.Indicators.Rules {
+			rule, err := models.NewIndicatorRule(source)
+			if err != nil {
+				return nil, errors.Wrapf(err, "indicator rule %d", i+1)
+			}
+
+			rules = append(rules, rule)
+		}
+	}
+
+	return rules, nil
+}
+
+// indicatorInterval returns the interval of the bars the entry indicators are computed over, 0 for a bar per price
+func (e Entry) indicatorInterval() time.Duration {
+	if e.Indicators == nil {
+		return 0
+	}
+
+	interval, _ := parseDuration("indicators interval", e.Indicators.Interval)
+
+	return interval
+}
+
+// threshold returns the base threshold of the entry, falling back to the threshold of the alerted direction when not given
+func (e Entry) threshold(direction models.Direction) float64 {
+	switch {
+	case e.Threshold != nil:
+		return *e.Threshold
+	case direction == models.DirectionDown && e.ThresholdDown != nil:
+		return *e.ThresholdDown
+	case e.ThresholdUp != nil:
+		return *e.ThresholdUp
+	default:
+		return 0
+	}
+}
+
+// priceLevels returns the price levels of the entry
+func (e Entry) priceLevels() []models.PriceLevel {
+	levels := make([]models.PriceLevel, 0, len(e.Levels))
+
+	for _, level := range e.Levels {
+		if level.Above != nil {
+			levels = append(levels, models.PriceLevel{Target: *level.Above, Direction: models.DirectionUp})
+		} else {
+			levels = append(levels, models.PriceLevel{Target: *level.Below, Direction: models.DirectionDown})
+		}
+	}
+
+	return levels
+}
+
+// valueOrZero returns the pointed value, 0 when nil
+func valueOrZero(value *float64) float64 {
+	if value == nil {
+		return 0
+	}
+
+	return *value
+}
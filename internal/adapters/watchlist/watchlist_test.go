@@ -0,0 +1,733 @@
+package watchlist
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"crypto-alert-bot/internal/adapters/mocks/mock_watchlist"
+	"crypto-alert-bot/internal/models"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name        string
+		fileName    string
+		content     string
+		wantPairs   []string
+		wantErr     bool
+		errContains string
+	}{
+		{
+			name:     "Valid yaml",
+			fileName: "watchlist.yaml",
+			content: `
+tickers:
+  - pair: btcusd
+    refresh_rate: 10
+    threshold: 2.5
+    lifetime: 3600
+  - pair: ETHEUR
+    refresh_rate: 30
+    threshold: 1
+`,
+			wantPairs: []string{"BTCUSD", "ETHEUR"},
+		},
+		{
+			name:      "Valid json",
+			fileName:  "watchlist.json",
+			content:   `{"tickers": [{"pair": "BTCUSD", "refresh_rate": 5, "threshold": 0.5}]}`,
+			wantPairs: []string{"BTCUSD"},
+		},
+		{
+			name:        "Unsupported extension",
+			fileName:    "watchlist.toml",
+			content:     `tickers = []`,
+			wantErr:     true,
+			errContains: "unsupported watchlist format",
+		},
+		{
+			name:     "Unknown field",
+			fileName: "watchlist.yaml",
+			content: `
+tickers:
+  - pair: BTCUSD
+    refresh: 10
+`,
+			wantErr:     true,
+			errContains: "error decoding yaml watchlist",
+		},
+		{
+			name:        "Empty watchlist",
+			fileName:    "watchlist.yaml",
+			content:     `tickers: []`,
+			wantErr:     true,
+			errContains: "watchlist has no tickers",
+		},
+		{
+			name:     "Routed to defined channels",
+			fileName: "watchlist.yaml",
+			content: `
+channels:
+  ops:
+    type: webhook
+    url: http://localhost/hook
+tickers:
+  - pair: BTCUSD
+    refresh_rate: 10
+    threshold: 1
+    channels: [ops, log]
+`,
+			wantPairs: []string{"BTCUSD"},
+		},
+		{
+			name:     "Routed to undefined channel",
+			fileName: "watchlist.yaml",
+			content: `
+tickers:
+  - pair: BTCUSD
+    refresh_rate: 10
+    threshold: 1
+    channels: [ops]
+`,
+			wantErr:     true,
+			errContains: `channel "ops" is not defined`,
+		},
+		{
+			name:     "Routed to the flag webhooks",
+			fileName: "watchlist.yaml",
+			content: `
+tickers:
+  - pair: BTCUSD
+    refresh_rate: 10
+    threshold: 1
+    channels: [webhooks]
+`,
+			wantPairs: []string{"BTCUSD"},
+		},
+		{
+			name:     "Redefined builtin channel",
+			fileName: "watchlist.yaml",
+			content: `
+channels:
+  webhooks:
+    type: webhook
+    url: http://localhost/hook
+tickers:
+  - pair: BTCUSD
+    refresh_rate: 10
+    threshold: 1
+`,
+			wantErr:     true,
+			errContains: `channel "webhooks" is builtin`,
+		},
+		{
+			name:     "Unsupported channel type",
+			fileName: "watchlist.yaml",
+			content: `
+channels:
+  ops:
+    type: pager
+    url: http://localhost/hook
+tickers:
+  - pair: BTCUSD
+    refresh_rate: 10
+    threshold: 1
+`,
+			wantErr:     true,
+			errContains: `channel "ops" has unsupported type "pager"`,
+		},
+		{
+			name:     "Telegram channel without chat",
+			fileName: "watchlist.yaml",
+			content: `
+channels:
+  family:
+    type: telegram
+    token_env: TELEGRAM_TOKEN
+tickers:
+  - pair: BTCUSD
+    refresh_rate: 10
+    threshold: 1
+`,
+			wantErr:     true,
+			errContains: `channel "family": token_env and chat_id are required`,
+		},
+		{
+			name:     "Email channel without recipients",
+			fileName: "watchlist.yaml",
+			content: `
+channels:
+  desk:
+    type: email
+    smtp_addr: localhost:25
+    from: bot@example.com
+tickers:
+  - pair: BTCUSD
+    refresh_rate: 10
+    threshold: 1
+`,
+			wantErr:     true,
+			errContains: `channel "desk": smtp_addr, from and to are required`,
+		},
+		{
+			name:     "Above rate limit",
+			fileName: "watchlist.yaml",
+			content: `
+tickers:
+  - pair: BTCUSD
+    refresh_rate: 0.1
+    threshold: 1
+`,
+			wantErr:     true,
+			errContains: "exceed the rate limit",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			validator := mock_watchlist.NewMockApiDataValidator(ctrl)
+			validator.EXPECT().IsExchangePairValid(gomock.Any(), gomock.Any()).Return(true, nil).AnyTimes()
+
+			path := filepath.Join(t.TempDir(), tt.fileName)
+			assert.NoError(t, os.WriteFile(path, []byte(tt.content), 0o600))
+
+			tickers, err := Load(path, validator, nil)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errContains)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Len(t, *tickers, len(tt.wantPairs))
+			for i, pair := range tt.wantPairs {
+				assert.Equal(t, pair, (*tickers)[i].Pair)
+			}
+		})
+	}
+}
+
+// resolvingValidator is a validator that also knows the quote currency of the pairs
+type resolvingValidator struct {
+	*mock_watchlist.MockApiDataValidator
+	*mock_watchlist.MockCurrencyResolver
+}
+
+func TestBuild(t *testing.T) {
+	t.Run("Maps entry values into the ticker config", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		validator := mock_watchlist.NewMockApiDataValidator(ctrl)
+		validator.EXPECT().IsExchangePairValid("kraken", "BTC-USD").Return(true, nil)
+
+		file, err := Parse([]byte("tickers:\n  - pair: btc-usd\n    exchange: Kraken\n    stream: true\n    refresh_rate: 10\n    threshold: 2.5\n    lifetime: 60\n    channels: [log]\n"), ".yaml")
+		assert.NoError(t, err)
+
+		tickers, err := file.Build(validator, nil)
+		assert.NoError(t, err)
+
+		ticker := (*tickers)[0]
+		assert.Equal(t, "BTC-USD", ticker.Pair)
+		assert.Equal(t, "kraken", ticker.Exchange)
+		assert.Equal(t, 10.0, ticker.Config.RefreshRate)
+		assert.Equal(t, 2.5, ticker.Config.PercOscillation)
+		assert.Equal(t, time.Duration(60), ticker.Config.Lifetime)
+		assert.True(t, ticker.Config.Stream)
+		assert.Equal(t, []string{"log"}, ticker.Config.Channels)
+	})
+
+	t.Run("Batches compact pairs with a known quote currency", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		content := []byte("tickers:\n  - {pair: BTCUSD, refresh_rate: 1, threshold: 2}\n  - {pair: ETHUSD, refresh_rate: 1, threshold: 2}\n  - {pair: XRPUSD, refresh_rate: 1, threshold: 2}\n")
+		limits := models.RateLimits{models.DefaultExchange: 100}
+
+		validator := mock_watchlist.NewMockApiDataValidator(ctrl)
+		validator.EXPECT().IsExchangePairValid(gomock.Any(), gomock.Any()).Return(true, nil).Times(6)
+
+		file, err := Parse(content, ".yaml")
+		assert.NoError(t, err)
+
+		_, err = file.Build(validator, limits)
+		assert.ErrorContains(t, err, "exceed the rate limit")
+
+		resolver := mock_watchlist.NewMockCurrencyResolver(ctrl)
+		resolver.EXPECT().PairCurrency("", gomock.Any()).Return("USD").Times(3)
+
+		tickers, err := file.Build(resolvingValidator{validator, resolver}, limits)
+		assert.NoError(t, err)
+		assert.Equal(t, "USD", (*tickers)[0].QuoteCurrency())
+	})
+
+	t.Run("Maps direction and directional thresholds", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		validator := mock_watchlist.NewMockApiDataValidator(ctrl)
+		validator.EXPECT().IsExchangePairValid(gomock.Any(), gomock.Any()).Return(true, nil).Times(2)
+
+		file, err := Parse([]byte(`{"tickers": [
+			{"pair": "BTCUSD", "refresh_rate": 10, "direction": "down", "threshold_down": 3},
+			{"pair": "ETHEUR", "refresh_rate": 10, "threshold": 2, "threshold_up": 5}
+		]}`), ".json")
+		assert.NoError(t, err)
+
+		tickers, err := file.Build(validator, nil)
+		assert.NoError(t, err)
+
+		dropOnly := (*tickers)[0].Config
+		assert.Equal(t, models.DirectionDown, dropOnly.Direction)
+		assert.Equal(t, 3.0, dropOnly.Threshold(models.DirectionDown))
+
+		both := (*tickers)[1].Config
+		assert.Equal(t, models.DirectionBoth, both.Direction)
+		assert.Equal(t, 5.0, both.Threshold(models.DirectionUp))
+		assert.Equal(t, 2.0, both.Threshold(models.DirectionDown))
+	})
+
+	t.Run("Rejects invalid directions", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		validator := mock_watchlist.NewMockApiDataValidator(ctrl)
+		validator.EXPECT().IsExchangePairValid(gomock.Any(), gomock.Any()).Return(true, nil).Times(2)
+
+		file, err := Parse([]byte(`{"tickers": [
+			{"pair": "BTCUSD", "refresh_rate": 10, "threshold": 1, "direction": "sideways"},
+			{"pair": "ETHEUR", "refresh_rate": 10, "direction": "up", "threshold_down": 3}
+		]}`), ".json")
+		assert.NoError(t, err)
+
+		_, err = file.Build(validator, nil)
+
+		assert.Contains(t, err.Error(), `entry 1 (BTCUSD): unknown direction "sideways", use up, down, both or none`)
+		assert.Contains(t, err.Error(), "entry 2 (ETHEUR): threshold is required")
+	})
+
+	t.Run("Maps the price source", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		validator := mock_watchlist.NewMockApiDataValidator(ctrl)
+		validator.EXPECT().IsExchangePairValid(gomock.Any(), gomock.Any()).Return(true, nil).Times(3)
+		validator.EXPECT().HasLastPrice("kraken").Return(true)
+		validator.EXPECT().HasLastPrice("").Return(false)
+
+		file, err := Parse([]byte(`{"tickers": [
+			{"pair": "BTCUSD", "refresh_rate": 10, "threshold": 1, "price_source": "bid"},
+			{"pair": "BTC-USD", "exchange": "kraken", "refresh_rate": 10, "threshold": 1, "price_source": "last"}
+		]}`), ".json")
+		assert.NoError(t, err)
+
+		tickers, err := file.Build(validator, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, models.PriceBid, (*tickers)[0].Config.PriceSource)
+		assert.Equal(t, models.PriceLast, (*tickers)[1].Config.PriceSource)
+
+		file, err = Parse([]byte(`{"tickers": [{"pair": "BTCUSD", "refresh_rate": 10, "threshold": 1, "price_source": "last"}]}`), ".json")
+		assert.NoError(t, err)
+
+		_, err = file.Build(validator, nil)
+		assert.Contains(t, err.Error(), "entry 1 (BTCUSD): price_source last is not provided by uphold")
+	})
+
+	t.Run("Maps price levels", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		validator := mock_watchlist.NewMockApiDataValidator(ctrl)
+		validator.EXPECT().IsExchangePairValid(gomock.Any(), gomock.Any()).Return(true, nil).Times(2)
+
+		file, err := Parse([]byte(`{"tickers": [
+			{"pair": "BTCUSD", "refresh_rate": 10, "levels": [{"above": 70000}, {"below": 60000}]},
+			{"pair": "ETHEUR", "refresh_rate": 10, "threshold": 1, "levels": [{"above": 4000, "below": 3000}, {"below": -1}]}
+		]}`), ".json")
+		assert.NoError(t, err)
+
+		_, err = file.Build(validator, nil)
+		assert.Contains(t, err.Error(), "entry 2 (ETHEUR): level 1 must set either above or below")
+		assert.Contains(t, err.Error(), "entry 2 (ETHEUR): level 2 must be positive, got -1")
+
+		file.Tickers = file.Tickers[:1]
+		validator.EXPECT().IsExchangePairValid(gomock.Any(), gomock.Any()).Return(true, nil)
+
+		tickers, err := file.Build(validator, nil)
+		assert.NoError(t, err)
+
+		config := (*tickers)[0].Config
+		assert.Equal(t, models.DirectionNone, config.Direction, "levels without thresholds only alert on the levels")
+		assert.Equal(t, []models.PriceLevel{
+			{Target: 70000, Direction: models.DirectionUp},
+			{Target: 60000, Direction: models.DirectionDown},
+		}, config.Levels)
+	})
+
+	t.Run("Maps the evaluation window", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		validator := mock_watchlist.NewMockApiDataValidator(ctrl)
+		validator.EXPECT().IsExchangePairValid(gomock.Any(), gomock.Any()).Return(true, nil).Times(3)
+
+		file, err := Parse([]byte(`{"tickers": [
+			{"pair": "BTCUSD", "refresh_rate": 10, "threshold": 5, "window": "15m", "window_baseline": "open"},
+			{"pair": "ETHEUR", "refresh_rate": 60, "threshold": 5, "window": "30s"},
+			{"pair": "XRPUSD", "refresh_rate": 10, "threshold": 5, "window": "soon", "window_baseline": "close"}
+		]}`), ".json")
+		assert.NoError(t, err)
+
+		_, err = file.Build(validator, nil)
+		assert.Contains(t, err.Error(), "entry 2 (ETHEUR): window 30s is shorter than refresh_rate 60s")
+		assert.Contains(t, err.Error(), `entry 3 (XRPUSD): invalid window "soon", use a duration such as 15m`)
+		assert.Contains(t, err.Error(), `entry 3 (XRPUSD): unknown window baseline "close", use extreme or open`)
+
+		file.Tickers = file.Tickers[:1]
+		validator.EXPECT().IsExchangePairValid(gomock.Any(), gomock.Any()).Return(true, nil)
+
+		tickers, err := file.Build(validator, nil)
+		assert.NoError(t, err)
+
+		config := (*tickers)[0].Config
+		assert.Equal(t, 15*time.Minute, config.Window)
+		assert.Equal(t, models.BaselineOpen, config.WindowBaseline)
+	})
+
+	t.Run("Maps the adaptive thresholds", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		validator := mock_watchlist.NewMockApiDataValidator(ctrl)
+		validator.EXPECT().IsExchangePairValid(gomock.Any(), gomock.Any()).Return(true, nil).Times(3)
+
+		file, err := Parse([]byte(`{"tickers": [
+			{"pair": "BTCUSD", "refresh_rate": 10, "zscore": 3, "volatility_window": "6h"},
+			{"pair": "ETHEUR", "refresh_rate": 10, "zscore": 0, "volatility_window": "1m"},
+			{"pair": "XRPUSD", "refresh_rate": 300, "zscore": 2}
+		]}`), ".json")
+		assert.NoError(t, err)
+
+		_, err = file.Build(validator, nil)
+		assert.Contains(t, err.Error(), "entry 2 (ETHEUR): zscore must be positive, got 0")
+		assert.Contains(t, err.Error(), "entry 2 (ETHEUR): volatility_window 1m0s holds fewer than 20 prices at refresh_rate 10s")
+		assert.Contains(t, err.Error(), "entry 3 (XRPUSD): volatility_window 1h0m0s holds fewer than 20 prices at refresh_rate 300s")
+		assert.NotContains(t, err.Error(), "threshold is required")
+
+		file.Tickers = file.Tickers[:1]
+		validator.EXPECT().IsExchangePairValid(gomock.Any(), gomock.Any()).Return(true, nil)
+
+		tickers, err := file.Build(validator, nil)
+		assert.NoError(t, err)
+
+		config := (*tickers)[0].Config
+		assert.Equal(t, 3.0, config.ZScore)
+		assert.Equal(t, 6*time.Hour, config.VolatilityWindow)
+		assert.Equal(t, 0.0, config.PercOscillation)
+	})
+
+	t.Run("Compiles the rule expressions", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		validator := mock_watchlist.NewMockApiDataValidator(ctrl)
+		validator.EXPECT().IsExchangePairValid(gomock.Any(), gomock.Any()).Return(true, nil).Times(2)
+
+		file, err := Parse([]byte(`{"tickers": [
+			{"pair": "BTCUSD", "refresh_rate": 10, "rules": ["pct_change(ask, 10m) > 3 && spread_pct < 0.5"]},
+			{"pair": "ETHEUR", "refresh_rate": 10, "threshold": 5, "rules": ["ask > 1", "pct_change(ask) > 3"]}
+		]}`), ".json")
+		assert.NoError(t, err)
+
+		_, err = file.Build(validator, nil)
+		assert.EqualError(t, err, "invalid watchlist, 1 problem(s) found:\n  - entry 2 (ETHEUR): rule 2: column 1: pct_change expects 2 argument(s), got 1")
+
+		file.Tickers = file.Tickers[:1]
+		validator.EXPECT().IsExchangePairValid(gomock.Any(), gomock.Any()).Return(true, nil)
+
+		tickers, err := file.Build(validator, nil)
+		assert.NoError(t, err)
+
+		config := (*tickers)[0].Config
+		assert.Equal(t, models.DirectionNone, config.Direction, "rules without thresholds only alert on the rules")
+		assert.Len(t, config.Rules, 2)
+		assert.Equal(t, models.ThresholdRule{}, config.Rules[0])
+		assert.Equal(t, "pct_change(ask, 10m) > 3 && spread_pct < 0.5", config.Rules[1].String())
+	})
+
+	t.Run("Maps the spread limits", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		validator := mock_watchlist.NewMockApiDataValidator(ctrl)
+		validator.EXPECT().IsExchangePairValid(gomock.Any(), gomock.Any()).Return(true, nil).Times(3)
+
+		file, err := Parse([]byte(`
+tickers:
+  - pair: BTCUSD
+    refresh_rate: 10
+    spread: {max_pct: 0.5, max: 20, for: 5m}
+  - pair: ETHEUR
+    refresh_rate: 10
+    spread: {for: 5m}
+  - pair: XRPUSD
+    refresh_rate: 10
+    spread: {max_pct: -1, for: soon}
+`), ".yaml")
+		assert.NoError(t, err)
+
+		_, err = file.Build(validator, nil)
+		assert.Contains(t, err.Error(), "entry 2 (ETHEUR): spread must set max_pct or max")
+		assert.Contains(t, err.Error(), "entry 3 (XRPUSD): spread max_pct must be positive, got -1")
+		assert.Contains(t, err.Error(), `entry 3 (XRPUSD): invalid spread for "soon", use a duration such as 15m`)
+
+		file.Tickers = file.Tickers[:1]
+		validator.EXPECT().IsExchangePairValid(gomock.Any(), gomock.Any()).Return(true, nil)
+
+		tickers, err := file.Build(validator, nil)
+		assert.NoError(t, err)
+
+		config := (*tickers)[0].Config
+		assert.Equal(t, models.DirectionNone, config.Direction, "spread limits without thresholds only alert on the spread")
+		assert.Equal(t, []models.Rule{models.ThresholdRule{}, &models.SpreadRule{MaxPct: 0.5, Max: 20, For: 5 * time.Minute}}, config.Rules)
+	})
+
+	t.Run("Parses the indicator rules", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		validator := mock_watchlist.NewMockApiDataValidator(ctrl)
+		validator.EXPECT().IsExchangePairValid(gomock.Any(), gomock.Any()).Return(true, nil).Times(3)
+
+		file, err := Parse([]byte(`
+tickers:
+  - pair: BTCUSD
+    refresh_rate: 10
+    indicators:
+      interval: 1m
+      rules: [ema(12) crosses ema(26), rsi(14) > 70]
+  - pair: ETHEUR
+    refresh_rate: 10
+    indicators: {interval: 1m}
+  - pair: XRPUSD
+    refresh_rate: 10
+    indicators:
+      interval: often
+      rules: [macd(12) > 0]
+`), ".yaml")
+		assert.NoError(t, err)
+
+		_, err = file.Build(validator, nil)
+		assert.Contains(t, err.Error(), "entry 2 (ETHEUR): indicators must set rules")
+		assert.Contains(t, err.Error(), `entry 3 (XRPUSD): indicator rule 1: unknown indicator "macd", use sma, ema, rsi, bb_upper or bb_lower`)
+		assert.Contains(t, err.Error(), `entry 3 (XRPUSD): invalid indicators interval "often", use a duration such as 15m`)
+
+		file.Tickers = file.Tickers[:1]
+		validator.EXPECT().IsExchangePairValid(gomock.Any(), gomock.Any()).Return(true, nil)
+
+		tickers, err := file.Build(validator, nil)
+		assert.NoError(t, err)
+
+		config := (*tickers)[0].Config
+		assert.Equal(t, models.DirectionNone, config.Direction, "indicators without thresholds only alert on the indicators")
+		assert.Equal(t, time.Minute, config.IndicatorInterval)
+		assert.Len(t, config.Rules, 3)
+		assert.Equal(t, "EMA(12) crosses EMA(26)", config.Rules[1].String())
+		assert.Equal(t, "RSI(14) > 70", config.Rules[2].String())
+	})
+
+	t.Run("Maps the peg", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		validator := mock_watchlist.NewMockApiDataValidator(ctrl)
+		validator.EXPECT().IsExchangePairValid(gomock.Any(), gomock.Any()).Return(true, nil).Times(3)
+
+		file, err := Parse([]byte(`
+tickers:
+  - pair: USDTUSD
+    refresh_rate: 10
+    peg: {target: 1, tolerance_pct: 0.5, for: 5m}
+  - pair: USDCUSD
+    refresh_rate: 10
+    peg: {tolerance_pct: 0}
+  - pair: DAIUSD
+    refresh_rate: 10
+    peg: {target: -1, tolerance_pct: 1, for: later}
+`), ".yaml")
+		assert.NoError(t, err)
+
+		_, err = file.Build(validator, nil)
+		assert.Contains(t, err.Error(), "entry 2 (USDCUSD): peg target is required")
+		assert.Contains(t, err.Error(), "entry 2 (USDCUSD): peg tolerance_pct must be positive, got 0")
+		assert.Contains(t, err.Error(), "entry 3 (DAIUSD): peg target must be positive, got -1")
+		assert.Contains(t, err.Error(), `entry 3 (DAIUSD): invalid peg for "later", use a duration such as 15m`)
+
+		file.Tickers = file.Tickers[:1]
+		validator.EXPECT().IsExchangePairValid(gomock.Any(), gomock.Any()).Return(true, nil)
+
+		tickers, err := file.Build(validator, nil)
+		assert.NoError(t, err)
+
+		config := (*tickers)[0].Config
+		assert.Equal(t, models.DirectionNone, config.Direction, "a peg without thresholds only alerts on the peg")
+		assert.Equal(t, []models.Rule{models.ThresholdRule{}, &models.PegRule{Target: 1, Tolerance: 0.5, For: 5 * time.Minute}}, config.Rules)
+	})
+
+	t.Run("Maps the cooldown and re-arm rules", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		validator := mock_watchlist.NewMockApiDataValidator(ctrl)
+		validator.EXPECT().IsExchangePairValid(gomock.Any(), gomock.Any()).Return(true, nil).Times(2)
+
+		file, err := Parse([]byte(`{"tickers": [
+			{"pair": "BTCUSD", "refresh_rate": 10, "threshold": 5, "cooldown": "10m", "rearm": 1.5},
+			{"pair": "ETHEUR", "refresh_rate": 10, "threshold": 5, "cooldown": "-1m", "rearm": 0}
+		]}`), ".json")
+		assert.NoError(t, err)
+
+		_, err = file.Build(validator, nil)
+		assert.Contains(t, err.Error(), "entry 2 (ETHEUR): cooldown must be positive, got -1m0s")
+		assert.Contains(t, err.Error(), "entry 2 (ETHEUR): rearm must be positive, got 0")
+
+		file.Tickers = file.Tickers[:1]
+		validator.EXPECT().IsExchangePairValid(gomock.Any(), gomock.Any()).Return(true, nil)
+
+		tickers, err := file.Build(validator, nil)
+		assert.NoError(t, err)
+
+		config := (*tickers)[0].Config
+		assert.Equal(t, 10*time.Minute, config.Cooldown)
+		assert.Equal(t, 1.5, config.Rearm)
+	})
+
+	t.Run("Links the arbitrage legs", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		validator := mock_watchlist.NewMockApiDataValidator(ctrl)
+		validator.EXPECT().IsExchangePairValid(gomock.Any(), gomock.Any()).Return(true, nil).AnyTimes()
+
+		file, err := Parse([]byte(`
+tickers:
+  - pair: BTC-USD
+    refresh_rate: 10
+    threshold: 5
+  - pair: BTC-USD
+    exchange: kraken
+    refresh_rate: 5
+    direction: none
+arbitrage:
+  - legs: [{pair: BTC-USD}, {pair: BTC-USD, exchange: kraken}]
+    threshold: 1
+    fee: 0.2
+`), ".yaml")
+		assert.NoError(t, err)
+
+		tickers, err := file.Build(validator, nil)
+		assert.NoError(t, err)
+
+		uphold, kraken := (*tickers)[0], (*tickers)[1]
+		assert.NotNil(t, uphold.Board)
+		assert.Same(t, uphold.Board, kraken.Board)
+
+		assert.Len(t, uphold.Config.Rules, 2)
+		assert.Equal(t, models.ThresholdRule{}, uphold.Config.Rules[0])
+
+		rule, ok := uphold.Config.Rules[1].(*models.ArbitrageRule)
+		assert.True(t, ok)
+		assert.Same(t, rule, kraken.Config.Rules[1])
+		assert.Equal(t, 20*time.Second, rule.MaxAge, "defaults to twice the slowest leg refresh rate")
+		assert.Equal(t, "divergence BTC-USD@uphold / BTC-USD@kraken > 1% after 0.2% fee", rule.String())
+
+		for _, tt := range []struct {
+			arbitrage string
+			wantErr   string
+		}{
+			{arbitrage: "{legs: [{pair: BTC-USD}], threshold: 1}", wantErr: "arbitrage 1 needs at least 2 legs"},
+			{arbitrage: "{legs: [{pair: BTC-USD}, {pair: BTC-USD, exchange: binance}], threshold: 1}", wantErr: "arbitrage 1 leg BTC-USD@binance is not on the watchlist"},
+			{arbitrage: "{legs: [{pair: BTC-USD}, {pair: BTC-USD, exchange: kraken}], threshold: 0}", wantErr: "arbitrage 1 threshold must be positive, got 0"},
+			{arbitrage: "{legs: [{pair: BTC-USD}, {pair: BTC-USD, exchange: kraken}], threshold: 1, max_age: 1x}", wantErr: `arbitrage 1: invalid max_age "1x", use a duration such as 15m`},
+			{arbitrage: "{legs: [{pair: BTC-USD}, {pair: BTCUSD}], threshold: 1}", wantErr: "arbitrage 1 leg BTCUSD@uphold is listed twice"},
+			{arbitrage: "{legs: [{pair: BTC-USD}, {pair: ETH-USD, exchange: kraken}], threshold: 1}", wantErr: "arbitrage 1 legs must quote the same pair, got BTC-USD@uphold and ETH-USD@kraken, write them in the BASE-QUOTE notation (e.g. BTC-USD)"},
+			{arbitrage: "{legs: [{pair: BTC-USD}, {pair: BTC-EUR, exchange: coinbase}], threshold: 1}", wantErr: "arbitrage 1 legs must quote the same pair, got BTC-USD@uphold and BTC-EUR@coinbase, write them in the BASE-QUOTE notation (e.g. BTC-USD)"},
+			{arbitrage: "{legs: [{pair: BTC-USD}, {pair: XBTUSD, exchange: kraken}], threshold: 1}", wantErr: "arbitrage 1 legs must quote the same pair, got BTC-USD@uphold and XBTUSD@kraken, write them in the BASE-QUOTE notation (e.g. BTC-USD)"},
+		} {
+			file, err := Parse([]byte(`tickers:
+  - {pair: BTC-USD, refresh_rate: 10, threshold: 5}
+  - {pair: BTC-USD, exchange: kraken, refresh_rate: 10, direction: none}
+  - {pair: ETH-USD, exchange: kraken, refresh_rate: 10, direction: none}
+  - {pair: XBTUSD, exchange: kraken, refresh_rate: 10, direction: none}
+  - {pair: BTC-EUR, exchange: coinbase, refresh_rate: 10, direction: none}
+arbitrage: [`+tt.arbitrage+"]"), ".yaml")
+			assert.NoError(t, err)
+
+			_, err = file.Build(validator, nil)
+			assert.EqualError(t, err, tt.wantErr)
+		}
+	})
+
+	t.Run("Maps the stale data watchdog", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		validator := mock_watchlist.NewMockApiDataValidator(ctrl)
+		validator.EXPECT().IsExchangePairValid(gomock.Any(), gomock.Any()).Return(true, nil).Times(3)
+
+		file, err := Parse([]byte(`{"tickers": [
+			{"pair": "BTCUSD", "refresh_rate": 10, "threshold": 5, "stale_after": "5m"},
+			{"pair": "ETHEUR", "refresh_rate": 60, "threshold": 5, "stale_after": "1m"},
+			{"pair": "XRPUSD", "refresh_rate": 10, "threshold": 5, "stale_after": "-5m"}
+		]}`), ".json")
+		assert.NoError(t, err)
+
+		_, err = file.Build(validator, nil)
+		assert.Contains(t, err.Error(), "entry 2 (ETHEUR): stale_after 1m0s must be longer than refresh_rate 60s")
+		assert.Contains(t, err.Error(), "entry 3 (XRPUSD): stale_after must be positive, got -5m0s")
+
+		file.Tickers = file.Tickers[:1]
+		validator.EXPECT().IsExchangePairValid(gomock.Any(), gomock.Any()).Return(true, nil)
+
+		tickers, err := file.Build(validator, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, 5*time.Minute, (*tickers)[0].Config.StaleAfter)
+	})
+
+	t.Run("Reports every invalid entry", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		validator := mock_watchlist.NewMockApiDataValidator(ctrl)
+		validator.EXPECT().IsExchangePairValid("", "BTCUSD").Return(true, nil)
+		validator.EXPECT().IsExchangePairValid("", "FAKE").Return(false, errors.New("pair doesn't exist, try again"))
+
+		file, err := Parse([]byte(`{"tickers": [
+			{"pair": "BTCUSD", "refresh_rate": -1, "threshold": 1},
+			{"pair": "FAKE", "refresh_rate": 10, "threshold": 1, "lifetime": -5},
+			{"refresh_rate": 10}
+		]}`), ".json")
+		assert.NoError(t, err)
+
+		_, err = file.Build(validator, nil)
+
+		var validationErr *ValidationError
+		assert.True(t, errors.As(err, &validationErr))
+		assert.Len(t, validationErr.Entries, 5)
+		assert.Contains(t, err.Error(), "entry 1 (BTCUSD): refresh_rate must be positive, got -1")
+		assert.Contains(t, err.Error(), "entry 2 (FAKE): pair doesn't exist, try again")
+		assert.Contains(t, err.Error(), "entry 2 (FAKE): lifetime can't be negative, got -5")
+		assert.Contains(t, err.Error(), "entry 3: pair is required")
+		assert.Contains(t, err.Error(), "entry 3: threshold is required")
+	})
+}
@@ -0,0 +1,254 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto-alert-bot/internal/adapters/api"
+	"crypto-alert-bot/internal/models"
+	"crypto-alert-bot/internal/services"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// SignatureHeader carries the HMAC-SHA256 signature of the request body, hex encoded and prefixed with "sha256="
+const SignatureHeader = "X-Signature-256"
+
+const (
+	queueSize       = 100
+	workers         = 4
+	deliveryTimeout = 10 * time.Second
+)
+
+//go:generate mockgen -source=$GOFILE -destination=../mocks/mock_webhook/mock_$GOFILE
+type DeliveryRecorder interface {
+	SaveDelivery(context.Context, models.Delivery) error
+}
+
+// Endpoint represents a webhook URL and the secret its payloads are signed with, unsigned when empty
+type Endpoint struct {
+	URL    string
+	Secret string
+}
+
+// Payload is the JSON document posted to the webhooks for every alert
+type Payload struct {
+	Type        models.AlertType       `json:"type"`
+	Title       string                 `json:"title"`
+	Pair        string                 `json:"pair"`
+	Exchange    string                 `json:"exchange"`
+	Direction   models.Direction       `json:"direction"`
+	PriceSource models.PriceSource     `json:"price_source"`
+	Price       float64                `json:"price"`
+	Ask         float64                `json:"ask"`
+	Bid         float64                `json:"bid"`
+	Spread      float64                `json:"spread"`
+	SpreadPct   float64                `json:"spread_pct"`
+	PriceChange float64                `json:"price_change"`
+	PercChange  float64                `json:"perc_change"`
+	Threshold   float64                `json:"threshold"`
+	ZScore      float64                `json:"zscore,omitempty"`
+	Target      float64                `json:"target,omitempty"`
+	Expression  string                 `json:"expression,omitempty"`
+	Divergence  *models.Divergence     `json:"divergence,omitempty"`
+	Indicators  models.IndicatorValues `json:"indicators,omitempty"`
+	Peg         *models.PegStatus      `json:"peg,omitempty"`
+	Stale       *models.StaleStatus    `json:"stale,omitempty"`
+	Suppressed  int                    `json:"suppressed"`
+	Timestamp   time.Time              `json:"timestamp"`
+}
+
+// delivery is a signed payload waiting to be posted to an endpoint
+type delivery struct {
+	endpoint Endpoint
+	payload  Payload
+	body     []byte
+}
+
+// Publisher posts the alerts to the webhook endpoints in the background, retrying failed deliveries with backoff
+type Publisher struct {
+	client      *http.Client
+	endpoints   []Endpoint
+	retryPolicy services.RetryPolicy
+	recorder    DeliveryRecorder
+	queue       chan delivery
+}
+
+// NewPublisher returns a new instance of Publisher, the deliveries are only recorded when a recorder is given
+func NewPublisher(client *http.Client, endpoints []Endpoint, retryPolicy services.RetryPolicy, recorder DeliveryRecorder) *Publisher {
+	if client == nil {
+		client = &http.Client{Timeout: deliveryTimeout}
+	}
+
+	return &Publisher{
+		client:      client,
+		endpoints:   endpoints,
+		retryPolicy: retryPolicy,
+		recorder:    recorder,
+		queue:       make(chan delivery, queueSize),
+	}
+}
+
+// NewPayload builds the webhook payload of the alert
+func NewPayload(alert *models.AlertView) Payload {
+	return Payload{
+		Type:        alert.Type,
+		Title:       alert.Title,
+		Pair:        alert.Pair,
+		Exchange:    alert.Exchange,
+		Direction:   alert.Direction,
+		PriceSource: alert.PriceSource,
+		Price:       alert.Price,
+		Ask:         alert.Ask,
+		Bid:         alert.Bid,
+		Spread:      alert.Spread,
+		SpreadPct:   alert.SpreadPct,
+		PriceChange: alert.PriceChange,
+		PercChange:  alert.PercChange,
+		Threshold:   alert.Threshold,
+		ZScore:      alert.ZScore,
+		Target:      alert.Target,
+		Expression:  alert.Rule,
+		Divergence:  alert.Divergence,
+		Indicators:  alert.Indicators,
+		Peg:         alert.Peg,
+		Stale:       alert.Stale,
+		Suppressed:  alert.Suppressed,
+		Timestamp:   alert.Timestamp,
+	}
+}
+
+// Sign returns the HMAC-SHA256 signature of the body with the secret, as sent on the SignatureHeader
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Publish queues the alert for delivery to every endpoint, dropping it when the queue is full
+func (p *Publisher) Publish(alert *models.AlertView) {
+	payload := NewPayload(alert)
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		slog.Error("error marshalling webhook payload", "pair", alert.Pair, "error", err)
+		return
+	}
+
+	for _, endpoint := range p.endpoints {
+		select {
+		case p.queue <- delivery{endpoint: endpoint, payload: payload, body: body}:
+		default:
+			slog.Warn("webhook queue full, dropping alert", "pair", alert.Pair, "endpoint", endpoint.URL)
+		}
+	}
+}
+
+// Run delivers the queued alerts until the context is done
+func (p *Publisher) Run(ctx context.Context) {
+	done := make(chan struct{})
+
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer func() { done <- struct{}{} }()
+
+			for {
+				select {
+				case d := <-p.queue:
+					p.deliver(ctx, d)
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	for i := 0; i < workers; i++ {
+		<-done
+	}
+}
+
+// deliver posts the payload to its endpoint, retrying network errors, 5xx and 429 responses, and records the outcome
+func (p *Publisher) deliver(ctx context.Context, d delivery) {
+	record := models.Delivery{
+		Endpoint:  d.endpoint.URL,
+		Pair:      d.payload.Pair,
+		AlertType: d.payload.Type,
+		Timestamp: d.payload.Timestamp,
+	}
+
+	var err error
+
+	for attempt := 1; ; attempt++ {
+		record.Attempts = attempt
+
+		record.StatusCode, err = p.post(ctx, d)
+		if err == nil || attempt >= p.retryPolicy.MaxAttempts || !services.IsRetryable(err) {
+			break
+		}
+
+		select {
+		case <-time.After(p.retryPolicy.Backoff(attempt)):
+		case <-ctx.Done():
+			err = ctx.Err()
+		}
+
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	record.Status = models.DeliveryDelivered
+
+	if err != nil {
+		record.Status = models.DeliveryFailed
+		record.Error = err.Error()
+
+		slog.Warn("webhook delivery failed", "endpoint", d.endpoint.URL, "pair", d.payload.Pair, "attempts", record.Attempts, "error", err)
+	}
+
+	if p.recorder == nil {
+		return
+	}
+
+	err = p.recorder.SaveDelivery(context.WithoutCancel(ctx), record)
+	if err != nil {
+		slog.Error("error saving webhook delivery", "endpoint", d.endpoint.URL, "pair", d.payload.Pair, "error", err)
+	}
+}
+
+// post sends the signed payload to the endpoint, returning the response status code
+func (p *Publisher) post(ctx context.Context, d delivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.endpoint.URL, bytes.NewReader(d.body))
+	if err != nil {
+		return 0, errors.Wrap(err, "error creating webhook request")
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	if d.endpoint.Secret != "" {
+		req.Header.Set(SignatureHeader, Sign(d.endpoint.Secret, d.body))
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return resp.StatusCode, &api.StatusError{StatusCode: resp.StatusCode}
+	}
+
+	return resp.StatusCode, nil
+}
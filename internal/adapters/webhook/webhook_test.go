@@ -0,0 +1,141 @@
+package webhook
+
+import (
+	"context"
+	mock_webhook "crypto-alert-bot/internal/adapters/mocks/mock_webhook"
+	"crypto-alert-bot/internal/models"
+	"crypto-alert-bot/internal/services"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestPublisherDeliver(t *testing.T) {
+	tests := []struct {
+		name         string
+		statuses     []int
+		wantStatus   models.DeliveryStatus
+		wantAttempts int
+		wantCode     int
+	}{
+		{
+			name:         "Delivered on the first attempt",
+			statuses:     []int{http.StatusOK},
+			wantStatus:   models.DeliveryDelivered,
+			wantAttempts: 1,
+			wantCode:     http.StatusOK,
+		},
+		{
+			name:         "Server errors are retried",
+			statuses:     []int{http.StatusInternalServerError, http.StatusTooManyRequests, http.StatusNoContent},
+			wantStatus:   models.DeliveryDelivered,
+			wantAttempts: 3,
+			wantCode:     http.StatusNoContent,
+		},
+		{
+			name:         "Client errors are not retried",
+			statuses:     []int{http.StatusBadRequest},
+			wantStatus:   models.DeliveryFailed,
+			wantAttempts: 1,
+			wantCode:     http.StatusBadRequest,
+		},
+		{
+			name:         "Fails after the last attempt",
+			statuses:     []int{http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway},
+			wantStatus:   models.DeliveryFailed,
+			wantAttempts: 3,
+			wantCode:     http.StatusBadGateway,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			payloads := make(chan Payload, len(tt.statuses))
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				call := int(calls.Add(1))
+
+				body, _ := io.ReadAll(r.Body)
+				assert.Equal(t, Sign("secret", body), r.Header.Get(SignatureHeader))
+
+				var payload Payload
+				json.Unmarshal(body, &payload)
+				payloads <- payload
+
+				w.WriteHeader(tt.statuses[call-1])
+			}))
+			defer server.Close()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			recorded := make(chan models.Delivery, 1)
+
+			recorder := mock_webhook.NewMockDeliveryRecorder(ctrl)
+			recorder.EXPECT().SaveDelivery(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, d models.Delivery) error {
+				recorded <- d
+				return nil
+			})
+
+			policy := services.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}
+			publisher := NewPublisher(server.Client(), []Endpoint{{URL: server.URL, Secret: "secret"}}, policy, recorder)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			go publisher.Run(ctx)
+
+			ticker := models.NewTicker("BTC-USD", 5, 1, 0)
+			ticker.CurrentAsk = 102
+			ticker.CurrentBid = 101
+			ticker.PriceChange = 2
+			ticker.PercChange = 2
+			timestamp := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+			publisher.Publish(models.NewAlertView(timestamp, ticker))
+
+			select {
+			case d := <-recorded:
+				assert.Equal(t, tt.wantStatus, d.Status)
+				assert.Equal(t, tt.wantAttempts, d.Attempts)
+				assert.Equal(t, tt.wantCode, d.StatusCode)
+				assert.Equal(t, server.URL, d.Endpoint)
+				assert.Equal(t, "BTC-USD", d.Pair)
+			case <-time.After(2 * time.Second):
+				t.Fatal("delivery was not recorded")
+			}
+
+			assert.Len(t, payloads, tt.wantAttempts)
+			assert.Equal(t, Payload{
+				Type:        models.AlertThreshold,
+				Title:       "▲ BTC-USD +2.00%",
+				Pair:        "BTC-USD",
+				Exchange:    models.DefaultExchange,
+				Direction:   models.DirectionUp,
+				PriceSource: models.PriceAsk,
+				Price:       102,
+				Ask:         102,
+				Bid:         101,
+				Spread:      1,
+				SpreadPct:   0.9852216748768473,
+				PriceChange: 2,
+				PercChange:  2,
+				Threshold:   1,
+				Timestamp:   timestamp,
+			}, <-payloads)
+		})
+	}
+}
+
+func TestSign(t *testing.T) {
+	assert.Equal(t, "sha256=f7bc83f430538424b13298e6aa6fb143ef4d59a14946175997479dbc2d1a3cd8",
+		Sign("key", []byte("The quick brown fox jumps over the lazy dog")))
+}
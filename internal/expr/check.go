@@ -0,0 +1,177 @@
+package expr
+
+import "time"
+
+// valueType is the type of an expression, checked before it's ever evaluated
+type valueType int
+
+const (
+	typeNumber valueType = iota
+	typeBool
+	typeDuration
+	typeSeries
+)
+
+func (t valueType) String() string {
+	switch t {
+	case typeBool:
+		return "bool"
+	case typeDuration:
+		return "duration"
+	case typeSeries:
+		return "series"
+	default:
+		return "number"
+	}
+}
+
+// assignableTo checks if a value of the type can be used where the wanted type is expected,
+// series are used as their current value wherever a number is expected
+func (t valueType) assignableTo(want valueType) bool {
+	return t == want || (t == typeSeries && want == typeNumber)
+}
+
+// function is a builtin function, the history functions read the series over the duration of their second argument
+type function struct {
+	params  []valueType
+	history func(values []float64) (float64, error)
+	number  func(value float64) float64
+}
+
+var functions = map[string]function{
+	"pct_change": {params: []valueType{typeSeries, typeDuration}, history: pctChange},
+	"change":     {params: []valueType{typeSeries, typeDuration}, history: change},
+	"min":        {params: []valueType{typeSeries, typeDuration}, history: minimum},
+	"max":        {params: []valueType{typeSeries, typeDuration}, history: maximum},
+	"avg":        {params: []valueType{typeSeries, typeDuration}, history: average},
+	"abs":        {params: []valueType{typeNumber}, number: abs},
+}
+
+// checker type checks the syntax tree and finds the longest history the expression reads
+type checker struct {
+	series   map[string]bool
+	lookback time.Duration
+}
+
+// check returns the type of the node, failing on unknown series and functions and on mismatched operands
+func (c *checker) check(n node) (valueType, error) {
+	switch n := n.(type) {
+	case *numberNode:
+		return typeNumber, nil
+	case *boolNode:
+		return typeBool, nil
+	case *durationNode:
+		if n.value <= 0 {
+			return 0, errorAt(n.pos, "duration must be positive, got %v", n.value)
+		}
+
+		return typeDuration, nil
+	case *seriesNode:
+		if !c.series[n.name] {
+			return 0, errorAt(n.pos, "unknown series %q", n.name)
+		}
+
+		return typeSeries, nil
+	case *unaryNode:
+		want := typeNumber
+		if n.operator == "!" {
+			want = typeBool
+		}
+
+		if err := c.expect(n.operand, want, n.operator); err != nil {
+			return 0, err
+		}
+
+		return want, nil
+	case *binaryNode:
+		return c.checkBinary(n)
+	case *callNode:
+		return c.checkCall(n)
+	default:
+		return 0, errorAt(n.position(), "unsupported expression")
+	}
+}
+
+// checkBinary checks the operands of the binary operation and returns its type
+func (c *checker) checkBinary(n *binaryNode) (valueType, error) {
+	switch n.operator {
+	case "&&", "||":
+		if err := c.expect(n.left, typeBool, n.operator); err != nil {
+			return 0, err
+		}
+
+		return typeBool, c.expect(n.right, typeBool, n.operator)
+	case "==", "!=":
+		left, err := c.check(n.left)
+		if err != nil {
+			return 0, err
+		}
+
+		if left == typeSeries {
+			left = typeNumber
+		}
+
+		if left == typeDuration {
+			return 0, errorAt(n.pos, "operator %s can't compare durations", n.operator)
+		}
+
+		n.boolOperands = left == typeBool
+
+		return typeBool, c.expect(n.right, left, n.operator)
+	case "<", "<=", ">", ">=":
+		if err := c.expect(n.left, typeNumber, n.operator); err != nil {
+			return 0, err
+		}
+
+		return typeBool, c.expect(n.right, typeNumber, n.operator)
+	default:
+		if err := c.expect(n.left, typeNumber, n.operator); err != nil {
+			return 0, err
+		}
+
+		return typeNumber, c.expect(n.right, typeNumber, n.operator)
+	}
+}
+
+// checkCall checks the function exists and its arguments, recording the duration of the history it reads
+func (c *checker) checkCall(n *callNode) (valueType, error) {
+	fn, ok := functions[n.name]
+	if !ok {
+		return 0, errorAt(n.pos, "unknown function %q", n.name)
+	}
+
+	if len(n.args) != len(fn.params) {
+		return 0, errorAt(n.pos, "%s expects %d argument(s), got %d", n.name, len(fn.params), len(n.args))
+	}
+
+	for i, param := range fn.params {
+		got, err := c.check(n.args[i])
+		if err != nil {
+			return 0, err
+		}
+
+		if !got.assignableTo(param) {
+			return 0, errorAt(n.args[i].position(), "argument %d of %s must be a %s, got %s", i+1, n.name, param, got)
+		}
+
+		if duration, ok := n.args[i].(*durationNode); ok {
+			c.lookback = max(c.lookback, duration.value)
+		}
+	}
+
+	return typeNumber, nil
+}
+
+// expect checks the operand is of the wanted type
+func (c *checker) expect(operand node, want valueType, operator string) error {
+	got, err := c.check(operand)
+	if err != nil {
+		return err
+	}
+
+	if !got.assignableTo(want) {
+		return errorAt(operand.position(), "operator %s expects a %s, got %s", operator, want, got)
+	}
+
+	return nil
+}
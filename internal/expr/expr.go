@@ -0,0 +1,249 @@
+// Package expr implements the rule expressions alert conditions are written in, such as
+// pct_change(ask, 10m) > 3 && spread_pct < 0.5. Expressions combine numbers, price series and the functions
+// over their history with arithmetic, comparison and logical operators, and are type checked when compiled
+package expr
+
+import (
+	"math"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Env provides the price series an expression is evaluated on
+type Env interface {
+	// Current returns the current value of the series
+	Current(series string) float64
+	// Since returns the values of the series recorded within the lookback duration, oldest first and ending with the current one
+	Since(series string, lookback time.Duration) []float64
+}
+
+// Program is a compiled expression, safe to evaluate concurrently
+type Program struct {
+	source   string
+	root     node
+	lookback time.Duration
+}
+
+// Compile parses and type checks the source, which must be a condition over the given series
+func Compile(source string, series ...string) (*Program, error) {
+	root, err := parse(source)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &checker{series: make(map[string]bool, len(series))}
+	for _, name := range series {
+		c.series[name] = true
+	}
+
+	result, err := c.check(root)
+	if err != nil {
+		return nil, err
+	}
+
+	if result != typeBool {
+		return nil, errors.Errorf("expression must be a condition, got %s", result)
+	}
+
+	return &Program{source: source, root: root, lookback: c.lookback}, nil
+}
+
+// String returns the source of the program
+func (p *Program) String() string {
+	return p.source
+}
+
+// Lookback returns the longest series history read by the program
+func (p *Program) Lookback() time.Duration {
+	return p.lookback
+}
+
+// Eval evaluates the condition on the environment, failing on divisions by zero and series without history
+func (p *Program) Eval(env Env) (bool, error) {
+	return evalBool(p.root, env)
+}
+
+// evalBool evaluates a node type checked as bool
+func evalBool(n node, env Env) (bool, error) {
+	switch n := n.(type) {
+	case *boolNode:
+		return n.value, nil
+	case *unaryNode:
+		operand, err := evalBool(n.operand, env)
+		return !operand, err
+	case *binaryNode:
+		switch n.operator {
+		case "&&", "||":
+			left, err := evalBool(n.left, env)
+			if err != nil || left == (n.operator == "||") {
+				return left, err
+			}
+
+			return evalBool(n.right, env)
+		case "==", "!=":
+			if n.boolOperands {
+				left, err := evalBool(n.left, env)
+				if err != nil {
+					return false, err
+				}
+
+				right, err := evalBool(n.right, env)
+				return (left == right) == (n.operator == "=="), err
+			}
+		}
+
+		left, err := evalNumber(n.left, env)
+		if err != nil {
+			return false, err
+		}
+
+		right, err := evalNumber(n.right, env)
+		if err != nil {
+			return false, err
+		}
+
+		return compare(n.operator, left, right), nil
+	default:
+		return false, errorAt(n.position(), "expression is not a condition")
+	}
+}
+
+// evalNumber evaluates a node type checked as number or series
+func evalNumber(n node, env Env) (float64, error) {
+	switch n := n.(type) {
+	case *numberNode:
+		return n.value, nil
+	case *seriesNode:
+		return env.Current(n.name), nil
+	case *unaryNode:
+		operand, err := evalNumber(n.operand, env)
+		return -operand, err
+	case *binaryNode:
+		left, err := evalNumber(n.left, env)
+		if err != nil {
+			return 0, err
+		}
+
+		right, err := evalNumber(n.right, env)
+		if err != nil {
+			return 0, err
+		}
+
+		return arithmetic(n, left, right)
+	case *callNode:
+		return call(n, env)
+	default:
+		return 0, errorAt(n.position(), "expression is not a number")
+	}
+}
+
+// call evaluates the builtin function
+func call(n *callNode, env Env) (float64, error) {
+	fn := functions[n.name]
+
+	if fn.history != nil {
+		series := n.args[0].(*seriesNode)
+		lookback := n.args[1].(*durationNode)
+
+		values := env.Since(series.name, lookback.value)
+		if len(values) == 0 {
+			return 0, errorAt(n.pos, "series %s has no history", series.name)
+		}
+
+		value, err := fn.history(values)
+		if err != nil {
+			return 0, errorAt(n.pos, "%s: %s", n.name, err)
+		}
+
+		return value, nil
+	}
+
+	arg, err := evalNumber(n.args[0], env)
+	if err != nil {
+		return 0, err
+	}
+
+	return fn.number(arg), nil
+}
+
+// arithmetic applies the arithmetic operator
+func arithmetic(n *binaryNode, left, right float64) (float64, error) {
+	switch n.operator {
+	case "+":
+		return left + right, nil
+	case "-":
+		return left - right, nil
+	case "*":
+		return left * right, nil
+	default:
+		if right == 0 {
+			return 0, errorAt(n.pos, "division by zero")
+		}
+
+		return left / right, nil
+	}
+}
+
+// compare applies the comparison operator
+func compare(operator string, left, right float64) bool {
+	switch operator {
+	case "<":
+		return left < right
+	case "<=":
+		return left <= right
+	case ">":
+		return left > right
+	case ">=":
+		return left >= right
+	case "==":
+		return left == right
+	default:
+		return left != right
+	}
+}
+
+// pctChange returns the percentage change from the oldest value to the current one
+func pctChange(values []float64) (float64, error) {
+	if values[0] == 0 {
+		return 0, errors.New("oldest value is 0")
+	}
+
+	return (values[len(values)-1] - values[0]) / values[0] * 100, nil
+}
+
+// change returns the change from the oldest value to the current one
+func change(values []float64) (float64, error) {
+	return values[len(values)-1] - values[0], nil
+}
+
+func minimum(values []float64) (float64, error) {
+	result := values[0]
+	for _, value := range values[1:] {
+		result = min(result, value)
+	}
+
+	return result, nil
+}
+
+func maximum(values []float64) (float64, error) {
+	result := values[0]
+	for _, value := range values[1:] {
+		result = max(result, value)
+	}
+
+	return result, nil
+}
+
+func average(values []float64) (float64, error) {
+	var sum float64
+	for _, value := range values {
+		sum += value
+	}
+
+	return sum / float64(len(values)), nil
+}
+
+func abs(value float64) float64 {
+	return math.Abs(value)
+}
@@ -0,0 +1,154 @@
+package expr
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testEnv holds the history of each series, one value per minute and ending with the current one
+type testEnv map[string][]float64
+
+func (e testEnv) Current(series string) float64 {
+	values := e[series]
+	return values[len(values)-1]
+}
+
+func (e testEnv) Since(series string, lookback time.Duration) []float64 {
+	values := e[series]
+	return values[max(0, len(values)-1-int(lookback/time.Minute)):]
+}
+
+var testSeries = []string{"ask", "bid", "spread_pct"}
+
+func TestCompile(t *testing.T) {
+	tests := []struct {
+		name         string
+		source       string
+		wantErr      string
+		wantLookback time.Duration
+	}{
+		{
+			name:         "Composite condition",
+			source:       "pct_change(ask, 10m) > 3 && spread_pct < 0.5",
+			wantLookback: 10 * time.Minute,
+		},
+		{
+			name:         "Longest lookback is kept",
+			source:       "avg(ask, 1h) > max(bid, 15m) || !(abs(change(bid, 5m)) <= 1)",
+			wantLookback: time.Hour,
+		},
+		{
+			name:   "Equality between conditions",
+			source: "(ask > bid) == true",
+		},
+		{
+			name:    "Unknown series",
+			source:  "ask > volume",
+			wantErr: `column 7: unknown series "volume"`,
+		},
+		{
+			name:    "Unknown function",
+			source:  "median(ask, 5m) > 1",
+			wantErr: `column 1: unknown function "median"`,
+		},
+		{
+			name:    "History functions need a series",
+			source:  "pct_change(ask * 2, 5m) > 1",
+			wantErr: "column 16: argument 1 of pct_change must be a series, got number",
+		},
+		{
+			name:    "Wrong argument count",
+			source:  "pct_change(ask) > 1",
+			wantErr: "column 1: pct_change expects 2 argument(s), got 1",
+		},
+		{
+			name:    "Durations only as arguments",
+			source:  "ask > 10m",
+			wantErr: "column 7: operator > expects a number, got duration",
+		},
+		{
+			name:    "Logical operators need conditions",
+			source:  "ask && bid > 1",
+			wantErr: "column 1: operator && expects a bool, got series",
+		},
+		{
+			name:    "Expression must be a condition",
+			source:  "ask - bid",
+			wantErr: "expression must be a condition, got number",
+		},
+		{
+			name:    "Syntax error",
+			source:  "ask > (bid",
+			wantErr: "column 11: expected ) but got end of expression",
+		},
+		{
+			name:    "Invalid duration",
+			source:  "pct_change(ask, 10x) > 1",
+			wantErr: `column 17: invalid duration "10x"`,
+		},
+		{
+			name:    "Unexpected character",
+			source:  "ask > bid ; 1",
+			wantErr: `column 11: unexpected character ';'`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			program, err := Compile(tt.source, testSeries...)
+
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.source, program.String())
+			assert.Equal(t, tt.wantLookback, program.Lookback())
+		})
+	}
+}
+
+func TestEval(t *testing.T) {
+	env := testEnv{
+		"ask":        {100, 101, 102, 104},
+		"bid":        {99, 100, 101, 103},
+		"spread_pct": {1, 1, 0.9, 0.4},
+	}
+
+	tests := []struct {
+		source  string
+		want    bool
+		wantErr string
+	}{
+		{source: "pct_change(ask, 10m) > 3 && spread_pct < 0.5", want: true},
+		{source: "pct_change(ask, 1m) > 3", want: false},
+		{source: "change(bid, 2m) == 3", want: true},
+		{source: "min(ask, 2m) == 101 && max(ask, 2m) == 104", want: true},
+		{source: "avg(bid, 3m) == 100.75", want: true},
+		{source: "-abs(bid - ask) < 0 != false", want: true},
+		{source: "1 + 2 * 3 == 7 && (1 + 2) * 3 == 9", want: true},
+		{source: "ask > bid || ask / 0 > 1", want: true},
+		{source: "ask / (bid - bid) > 1", wantErr: "column 5: division by zero"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			program, err := Compile(tt.source, testSeries...)
+			require.NoError(t, err)
+
+			got, err := program.Eval(env)
+
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package expr
+
+import (
+	"strconv"
+	"time"
+	"unicode"
+
+	"github.com/pkg/errors"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenNumber
+	tokenDuration
+	tokenIdent
+	tokenOperator
+	tokenLParen
+	tokenRParen
+	tokenComma
+)
+
+// token is a lexical unit of an expression, pos is its offset in the source
+type token struct {
+	kind     tokenKind
+	text     string
+	pos      int
+	number   float64
+	duration time.Duration
+}
+
+// operators lists the operators of the language, the two characters ones first so they are matched before their prefixes
+var operators = []string{"&&", "||", "==", "!=", "<=", ">=", "<", ">", "+", "-", "*", "/", "!"}
+
+// lex splits the source into tokens, ending with a tokenEOF
+func lex(source string) ([]token, error) {
+	var tokens []token
+
+	for pos := 0; pos < len(source); {
+		c := rune(source[pos])
+
+		switch {
+		case unicode.IsSpace(c):
+			pos++
+		case c == '(':
+			tokens = append(tokens, token{kind: tokenLParen, text: "(", pos: pos})
+			pos++
+		case c == ')':
+			tokens = append(tokens, token{kind: tokenRParen, text: ")", pos: pos})
+			pos++
+		case c == ',':
+			tokens = append(tokens, token{kind: tokenComma, text: ",", pos: pos})
+			pos++
+		case unicode.IsDigit(c) || c == '.':
+			tok, err := lexNumber(source, pos)
+			if err != nil {
+				return nil, err
+			}
+
+			tokens = append(tokens, tok)
+			pos += len(tok.text)
+		case unicode.IsLetter(c) || c == '_':
+			end := pos
+			for end < len(source) && isIdentChar(rune(source[end])) {
+				end++
+			}
+
+			tokens = append(tokens, token{kind: tokenIdent, text: source[pos:end], pos: pos})
+			pos = end
+		default:
+			operator := matchOperator(source[pos:])
+			if operator == "" {
+				return nil, errorAt(pos, "unexpected character %q", c)
+			}
+
+			tokens = append(tokens, token{kind: tokenOperator, text: operator, pos: pos})
+			pos += len(operator)
+		}
+	}
+
+	return append(tokens, token{kind: tokenEOF, pos: len(source)}), nil
+}
+
+// lexNumber reads a number, or a duration when the number is followed by a unit such as 10m or 1h30m
+func lexNumber(source string, pos int) (token, error) {
+	end := pos
+	for end < len(source) && (unicode.IsDigit(rune(source[end])) || source[end] == '.') {
+		end++
+	}
+
+	if end < len(source) && unicode.IsLetter(rune(source[end])) {
+		for end < len(source) && (unicode.IsLetter(rune(source[end])) || unicode.IsDigit(rune(source[end])) || source[end] == '.') {
+			end++
+		}
+
+		duration, err := time.ParseDuration(source[pos:end])
+		if err != nil {
+			return token{}, errorAt(pos, "invalid duration %q", source[pos:end])
+		}
+
+		return token{kind: tokenDuration, text: source[pos:end], pos: pos, duration: duration}, nil
+	}
+
+	number, err := strconv.ParseFloat(source[pos:end], 64)
+	if err != nil {
+		return token{}, errorAt(pos, "invalid number %q", source[pos:end])
+	}
+
+	return token{kind: tokenNumber, text: source[pos:end], pos: pos, number: number}, nil
+}
+
+// matchOperator returns the operator the source starts with, empty when none
+func matchOperator(source string) string {
+	for _, operator := range operators {
+		if len(source) >= len(operator) && source[:len(operator)] == operator {
+			return operator
+		}
+	}
+
+	return ""
+}
+
+func isIdentChar(c rune) bool {
+	return unicode.IsLetter(c) || unicode.IsDigit(c) || c == '_'
+}
+
+// errorAt returns an error pointing to the column of the source it was found on
+func errorAt(pos int, format string, args ...any) error {
+	return errors.Errorf("column %d: %s", pos+1, errors.Errorf(format, args...))
+}
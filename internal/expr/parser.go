@@ -0,0 +1,226 @@
+package expr
+
+import "time"
+
+// node is an expression of the syntax tree
+type node interface {
+	position() int
+}
+
+type numberNode struct {
+	pos   int
+	value float64
+}
+
+type durationNode struct {
+	pos   int
+	value time.Duration
+}
+
+type boolNode struct {
+	pos   int
+	value bool
+}
+
+// seriesNode references a price series, evaluated to its current value unless given to a function over its history
+type seriesNode struct {
+	pos  int
+	name string
+}
+
+type unaryNode struct {
+	pos      int
+	operator string
+	operand  node
+}
+
+// binaryNode is a binary operation, boolOperands is set by the checker on equalities between conditions
+type binaryNode struct {
+	pos          int
+	operator     string
+	left         node
+	right        node
+	boolOperands bool
+}
+
+type callNode struct {
+	pos  int
+	name string
+	args []node
+}
+
+func (n *numberNode) position() int   { return n.pos }
+func (n *durationNode) position() int { return n.pos }
+func (n *boolNode) position() int     { return n.pos }
+func (n *seriesNode) position() int   { return n.pos }
+func (n *unaryNode) position() int    { return n.pos }
+func (n *binaryNode) position() int   { return n.pos }
+func (n *callNode) position() int     { return n.pos }
+
+// precedence returns the binding power of the binary operators, higher binds tighter
+var precedence = map[string]int{
+	"||": 1,
+	"&&": 2,
+	"==": 3, "!=": 3,
+	"<": 4, "<=": 4, ">": 4, ">=": 4,
+	"+": 5, "-": 5,
+	"*": 6, "/": 6,
+}
+
+// parser builds the syntax tree of the tokens with precedence climbing
+type parser struct {
+	tokens []token
+	next   int
+}
+
+// parse returns the syntax tree of the source
+func parse(source string) (node, error) {
+	tokens, err := lex(source)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+
+	root, err := p.parseBinary(1)
+	if err != nil {
+		return nil, err
+	}
+
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, errorAt(tok.pos, "unexpected %q", tok.text)
+	}
+
+	return root, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.next]
+}
+
+func (p *parser) advance() token {
+	tok := p.tokens[p.next]
+	if tok.kind != tokenEOF {
+		p.next++
+	}
+
+	return tok
+}
+
+// parseBinary parses the binary operations binding at least as tight as the minimum precedence
+func (p *parser) parseBinary(minPrecedence int) (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		tok := p.peek()
+
+		prec, ok := precedence[tok.text]
+		if tok.kind != tokenOperator || !ok || prec < minPrecedence {
+			return left, nil
+		}
+
+		p.advance()
+
+		right, err := p.parseBinary(prec + 1)
+		if err != nil {
+			return nil, err
+		}
+
+		left = &binaryNode{pos: tok.pos, operator: tok.text, left: left, right: right}
+	}
+}
+
+// parseUnary parses the negations and the operands
+func (p *parser) parseUnary() (node, error) {
+	tok := p.peek()
+
+	if tok.kind == tokenOperator && (tok.text == "!" || tok.text == "-") {
+		p.advance()
+
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		return &unaryNode{pos: tok.pos, operator: tok.text, operand: operand}, nil
+	}
+
+	return p.parseOperand()
+}
+
+// parseOperand parses the literals, series, function calls and parenthesized expressions
+func (p *parser) parseOperand() (node, error) {
+	tok := p.advance()
+
+	switch tok.kind {
+	case tokenNumber:
+		return &numberNode{pos: tok.pos, value: tok.number}, nil
+	case tokenDuration:
+		return &durationNode{pos: tok.pos, value: tok.duration}, nil
+	case tokenLParen:
+		inner, err := p.parseBinary(1)
+		if err != nil {
+			return nil, err
+		}
+
+		if closing := p.advance(); closing.kind != tokenRParen {
+			return nil, errorAt(closing.pos, "expected ) but got %s", describe(closing))
+		}
+
+		return inner, nil
+	case tokenIdent:
+		switch tok.text {
+		case "true", "false":
+			return &boolNode{pos: tok.pos, value: tok.text == "true"}, nil
+		}
+
+		if p.peek().kind == tokenLParen {
+			p.advance()
+			return p.parseCall(tok)
+		}
+
+		return &seriesNode{pos: tok.pos, name: tok.text}, nil
+	default:
+		return nil, errorAt(tok.pos, "expected a value but got %s", describe(tok))
+	}
+}
+
+// parseCall parses the arguments of the function call, after its opening parenthesis
+func (p *parser) parseCall(name token) (node, error) {
+	call := &callNode{pos: name.pos, name: name.text}
+
+	if p.peek().kind == tokenRParen {
+		p.advance()
+		return call, nil
+	}
+
+	for {
+		arg, err := p.parseBinary(1)
+		if err != nil {
+			return nil, err
+		}
+
+		call.args = append(call.args, arg)
+
+		switch tok := p.advance(); tok.kind {
+		case tokenComma:
+			continue
+		case tokenRParen:
+			return call, nil
+		default:
+			return nil, errorAt(tok.pos, "expected , or ) but got %s", describe(tok))
+		}
+	}
+}
+
+// describe returns how the token is referred to on errors
+func describe(tok token) string {
+	if tok.kind == tokenEOF {
+		return "end of expression"
+	}
+
+	return "\"" + tok.text + "\""
+}
@@ -0,0 +1,53 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: fanout.go
+//
+// Generated by this command:
+//
+//	mockgen -source=fanout.go -destination=../mocks/mock_fanout/mock_fanout.go
+//
+
+// Package mock_services is a generated GoMock package.
+package mock_services
+
+import (
+	models "crypto-alert-bot/internal/models"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAlertPublisher is a mock of AlertPublisher interface.
+type MockAlertPublisher struct {
+	ctrl     *gomock.Controller
+	recorder *MockAlertPublisherMockRecorder
+	isgomock struct{}
+}
+
+// MockAlertPublisherMockRecorder is the mock recorder for MockAlertPublisher.
+type MockAlertPublisherMockRecorder struct {
+	mock *MockAlertPublisher
+}
+
+// NewMockAlertPublisher creates a new mock instance.
+func NewMockAlertPublisher(ctrl *gomock.Controller) *MockAlertPublisher {
+	mock := &MockAlertPublisher{ctrl: ctrl}
+	mock.recorder = &MockAlertPublisherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAlertPublisher) EXPECT() *MockAlertPublisherMockRecorder {
+	return m.recorder
+}
+
+// Publish mocks base method.
+func (m *MockAlertPublisher) Publish(arg0 *models.AlertView) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Publish", arg0)
+}
+
+// Publish indicates an expected call of Publish.
+func (mr *MockAlertPublisherMockRecorder) Publish(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*MockAlertPublisher)(nil).Publish), arg0)
+}
@@ -0,0 +1,72 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: history.go
+//
+// Generated by this command:
+//
+//	mockgen -source=history.go -destination=../mocks/mock_scheduler/mock_history.go
+//
+
+// Package mock_services is a generated GoMock package.
+package mock_services
+
+import (
+	context "context"
+	models "crypto-alert-bot/internal/models"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAlertReader is a mock of AlertReader interface.
+type MockAlertReader struct {
+	ctrl     *gomock.Controller
+	recorder *MockAlertReaderMockRecorder
+	isgomock struct{}
+}
+
+// MockAlertReaderMockRecorder is the mock recorder for MockAlertReader.
+type MockAlertReaderMockRecorder struct {
+	mock *MockAlertReader
+}
+
+// NewMockAlertReader creates a new mock instance.
+func NewMockAlertReader(ctrl *gomock.Controller) *MockAlertReader {
+	mock := &MockAlertReader{ctrl: ctrl}
+	mock.recorder = &MockAlertReaderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAlertReader) EXPECT() *MockAlertReaderMockRecorder {
+	return m.recorder
+}
+
+// AggregateAlerts mocks base method.
+func (m *MockAlertReader) AggregateAlerts(arg0 context.Context, arg1 models.AlertFilter, arg2 models.BucketSize) ([]models.AlertBucket, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AggregateAlerts", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]models.AlertBucket)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AggregateAlerts indicates an expected call of AggregateAlerts.
+func (mr *MockAlertReaderMockRecorder) AggregateAlerts(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AggregateAlerts", reflect.TypeOf((*MockAlertReader)(nil).AggregateAlerts), arg0, arg1, arg2)
+}
+
+// QueryAlerts mocks base method.
+func (m *MockAlertReader) QueryAlerts(arg0 context.Context, arg1 models.AlertFilter, arg2 models.Page) (models.AlertPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueryAlerts", arg0, arg1, arg2)
+	ret0, _ := ret[0].(models.AlertPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryAlerts indicates an expected call of QueryAlerts.
+func (mr *MockAlertReaderMockRecorder) QueryAlerts(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryAlerts", reflect.TypeOf((*MockAlertReader)(nil).QueryAlerts), arg0, arg1, arg2)
+}
@@ -11,9 +11,9 @@ package mock_services
 
 import (
 	context "context"
+	models "crypto-alert-bot/internal/models"
 	reflect "reflect"
 	time "time"
-	models "crypto-alert-bot/internal/models"
 
 	gomock "go.uber.org/mock/gomock"
 )
@@ -56,6 +56,45 @@ func (mr *MockDataRetrieverMockRecorder) FetchPairData(arg0, arg1 any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchPairData", reflect.TypeOf((*MockDataRetriever)(nil).FetchPairData), arg0, arg1)
 }
 
+// MockQuoteStreamer is a mock of QuoteStreamer interface.
+type MockQuoteStreamer struct {
+	ctrl     *gomock.Controller
+	recorder *MockQuoteStreamerMockRecorder
+	isgomock struct{}
+}
+
+// MockQuoteStreamerMockRecorder is the mock recorder for MockQuoteStreamer.
+type MockQuoteStreamerMockRecorder struct {
+	mock *MockQuoteStreamer
+}
+
+// NewMockQuoteStreamer creates a new mock instance.
+func NewMockQuoteStreamer(ctrl *gomock.Controller) *MockQuoteStreamer {
+	mock := &MockQuoteStreamer{ctrl: ctrl}
+	mock.recorder = &MockQuoteStreamerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockQuoteStreamer) EXPECT() *MockQuoteStreamerMockRecorder {
+	return m.recorder
+}
+
+// Subscribe mocks base method.
+func (m *MockQuoteStreamer) Subscribe(arg0 context.Context, arg1 *models.Ticker) (<-chan models.Quote, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Subscribe", arg0, arg1)
+	ret0, _ := ret[0].(<-chan models.Quote)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Subscribe indicates an expected call of Subscribe.
+func (mr *MockQuoteStreamerMockRecorder) Subscribe(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribe", reflect.TypeOf((*MockQuoteStreamer)(nil).Subscribe), arg0, arg1)
+}
+
 // MockPublisher is a mock of Publisher interface.
 type MockPublisher struct {
 	ctrl     *gomock.Controller
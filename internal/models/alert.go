@@ -0,0 +1,51 @@
+package models
+
+import "time"
+
+// AlertType identifies the reason a ticker is published
+type AlertType string
+
+const (
+	// AlertThreshold is published when the price change goes above the ticker threshold
+	AlertThreshold AlertType = "threshold"
+	// AlertSourceDown is published when the ticker exchange stops answering and polling is paused
+	AlertSourceDown AlertType = "source_down"
+	// AlertSourceUp is published when the ticker exchange answers again after being down
+	AlertSourceUp AlertType = "source_up"
+	// AlertLevel is published when the price crosses one of the ticker price levels
+	AlertLevel AlertType = "level"
+	// AlertExpression is published when one of the ticker rule expressions holds
+	AlertExpression AlertType = "expression"
+	// AlertSpread is published when the bid/ask spread widens beyond the ticker spread limits
+	AlertSpread AlertType = "spread"
+	// AlertArbitrage is published when the quotes of the same asset diverge across exchanges beyond the fees
+	AlertArbitrage AlertType = "arbitrage"
+	// AlertIndicator is published when one of the ticker indicator rules fires
+	AlertIndicator AlertType = "indicator"
+	// AlertDepeg is published when a pegged price stays outside the tolerance band of its peg
+	AlertDepeg AlertType = "depeg"
+	// AlertPegRecovered is published when a de-pegged price re-enters the tolerance band of its peg
+	AlertPegRecovered AlertType = "peg_recovered"
+	// AlertStale is published when the quotes of a ticker stop changing or stop being received
+	AlertStale AlertType = "stale"
+)
+
+// Alert represents an alert stored after a ticker went above its threshold or crossed a price level
+type Alert struct {
+	ID          int
+	Pair        string
+	RuleType    AlertType
+	Target      float64
+	Expression  string
+	Indicators  IndicatorValues
+	PriceChange float64
+	PercChange  float64
+	FinalPrice  float64
+	Ask         float64
+	Bid         float64
+	PriceSource PriceSource
+	Direction   Direction
+	Suppressed  int
+	Timestamp   time.Time
+	Config      TickerConfig
+}
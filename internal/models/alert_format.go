@@ -0,0 +1,141 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// AlertTitle returns the one line summary of the ticker alert published at the given time, shared by every alert channel
+func (t *Ticker) AlertTitle(at time.Time) string {
+	switch t.AlertType {
+	case AlertSourceDown:
+		return fmt.Sprintf("⚠ %s is down, polling of %s paused", t.ExchangeName(), t.Pair)
+	case AlertSourceUp:
+		return fmt.Sprintf("✔ %s recovered, polling of %s resumed", t.ExchangeName(), t.Pair)
+	case AlertStale:
+		return t.staleTitle(at)
+	case AlertLevel:
+		if t.Level == nil {
+			return fmt.Sprintf("%s %s crossed a price level", t.arrow(), t.Pair)
+		}
+
+		return fmt.Sprintf("%s %s crossed %s %g", t.arrow(), t.Pair, crossing(t.Level.Direction), t.Level.Target)
+	case AlertExpression:
+		return fmt.Sprintf("◆ %s matched %s", t.Pair, t.RuleSource())
+	case AlertSpread:
+		return fmt.Sprintf("↔ %s spread widened to %.4g%%", t.Pair, t.SpreadPct())
+	case AlertArbitrage:
+		if d := t.Divergence; d != nil {
+			return fmt.Sprintf("⇄ buy %s at %.8g, sell %s at %.8g: %+.2f%% after fees", d.Buy, d.Ask, d.Sell, d.Bid, d.Net)
+		}
+
+		return fmt.Sprintf("⇄ %s diverged across exchanges", t.Pair)
+	case AlertIndicator:
+		return fmt.Sprintf("∿ %s %s", t.Pair, t.RuleSource())
+	case AlertDepeg, AlertPegRecovered:
+		return t.pegTitle()
+	default:
+		return fmt.Sprintf("%s %s %+.2f%%", t.arrow(), t.Pair, t.PercChange)
+	}
+}
+
+// AlertLimit returns the name and value of the limit the price went past, the target of level alerts, the rule of
+// expression, spread, arbitrage, indicator and peg alerts or the threshold otherwise, along with its z-score when adaptive
+func (t *Ticker) AlertLimit() (string, string) {
+	if t.AlertType == AlertLevel {
+		if t.Level == nil {
+			return "Target", ""
+		}
+
+		return "Target", fmt.Sprintf("%g", t.Level.Target)
+	}
+
+	if t.IsRuleAlert() {
+		return "Rule", t.RuleSource()
+	}
+
+	threshold := t.Threshold(t.ChangeDirection())
+
+	if t.Config.ZScore > 0 {
+		return "Threshold", fmt.Sprintf("%.4g%% (%gσ)", threshold, t.Config.ZScore)
+	}
+
+	return "Threshold", fmt.Sprintf("%g%%", threshold)
+}
+
+// IsPriceAlert checks if the alert carries a price change, unlike the data source and stale data alerts
+func (t *Ticker) IsPriceAlert() bool {
+	switch t.AlertType {
+	case AlertSourceDown, AlertSourceUp, AlertStale:
+		return false
+	default:
+		return true
+	}
+}
+
+// IsRuleAlert checks if the alert was fired by one of the ticker rules other than the threshold
+func (t *Ticker) IsRuleAlert() bool {
+	switch t.AlertType {
+	case AlertExpression, AlertSpread, AlertArbitrage, AlertIndicator, AlertDepeg, AlertPegRecovered:
+		return true
+	default:
+		return false
+	}
+}
+
+// RuleSource returns the source of the rule that fired, empty for threshold and level alerts
+func (t *Ticker) RuleSource() string {
+	if t.AlertType == AlertThreshold || t.AlertType == "" || t.Rule == nil {
+		return ""
+	}
+
+	return t.Rule.String()
+}
+
+// arrow returns the arrow pointing to the direction of the price change
+func (t *Ticker) arrow() string {
+	if t.ChangeDirection() == DirectionUp {
+		return "▲"
+	}
+
+	return "▼"
+}
+
+// crossing returns the word describing a level crossed upwards or downwards
+func crossing(direction Direction) string {
+	if direction == DirectionUp {
+		return "above"
+	}
+
+	return "below"
+}
+
+// pegTitle returns the one line summary of a de-peg or of its recovery
+func (t *Ticker) pegTitle() string {
+	peg := t.Peg
+	if peg == nil {
+		return fmt.Sprintf("⚓ %s %s", t.Pair, t.RuleSource())
+	}
+
+	if peg.Recovered {
+		return fmt.Sprintf("⚓ %s back within %g%% of its %g peg after %s", t.Pair, peg.Tolerance, peg.Target, peg.Outside())
+	}
+
+	return fmt.Sprintf("⚓ %s de-pegged to %.6g, %+.2f%% from %g for %s", t.Pair, t.Price().Float64(), peg.Deviation, peg.Target, peg.Outside())
+}
+
+// staleTitle returns the one line summary of the stale data, stale for as long as it was at the given time
+func (t *Ticker) staleTitle(at time.Time) string {
+	stale := t.Stale
+	if stale == nil {
+		return fmt.Sprintf("⏸ %s data from %s is stale", t.Pair, t.ExchangeName())
+	}
+
+	duration := at.Sub(stale.Since).Round(time.Second)
+
+	if stale.Reason == StaleNoData {
+		return fmt.Sprintf("⏸ no %s quote from %s for %s", t.Pair, t.ExchangeName(), duration)
+	}
+
+	return fmt.Sprintf("⏸ %s quotes from %s unchanged for %s", t.Pair, t.ExchangeName(), duration)
+}
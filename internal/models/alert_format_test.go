@@ -0,0 +1,127 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAlertTitle(t *testing.T) {
+	at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	rsi, _ := NewIndicatorRule("rsi(14) > 70")
+
+	tests := []struct {
+		name   string
+		ticker *Ticker
+		want   string
+	}{
+		{
+			name:   "Price rise",
+			ticker: &Ticker{Pair: "ETHEUR", PriceChange: 5, PercChange: 5},
+			want:   "▲ ETHEUR +5.00%",
+		},
+		{
+			name:   "Price drop",
+			ticker: &Ticker{Pair: "ETHEUR", AlertType: AlertThreshold, PriceChange: -5, PercChange: -5},
+			want:   "▼ ETHEUR -5.00%",
+		},
+		{
+			name:   "Source down",
+			ticker: &Ticker{Pair: "ETHEUR", AlertType: AlertSourceDown},
+			want:   "⚠ uphold is down, polling of ETHEUR paused",
+		},
+		{
+			name:   "Source recovered",
+			ticker: &Ticker{Pair: "ETHEUR", AlertType: AlertSourceUp},
+			want:   "✔ uphold recovered, polling of ETHEUR resumed",
+		},
+		{
+			name:   "Level crossed downwards",
+			ticker: &Ticker{Pair: "BTCUSD", AlertType: AlertLevel, Level: &PriceLevel{Target: 60000, Direction: DirectionDown}},
+			want:   "▼ BTCUSD crossed below 60000",
+		},
+		{
+			name:   "Indicator",
+			ticker: &Ticker{Pair: "BTCUSD", AlertType: AlertIndicator, Rule: rsi},
+			want:   "∿ BTCUSD RSI(14) > 70",
+		},
+		{
+			name: "Arbitrage",
+			ticker: &Ticker{Pair: "BTC-USD", AlertType: AlertArbitrage, Divergence: &Divergence{
+				Buy: ArbitrageLeg{Exchange: "kraken", Pair: "BTC-USD"}, Ask: 100, Sell: ArbitrageLeg{Pair: "BTC-USD"}, Bid: 102, Net: 1.5}},
+			want: "⇄ buy BTC-USD@kraken at 100, sell BTC-USD@uphold at 102: +1.50% after fees",
+		},
+		{
+			name: "De-peg",
+			ticker: &Ticker{Pair: "USDTUSD", AlertType: AlertDepeg, CurrentAsk: 0.985,
+				Peg: &PegStatus{Target: 1, Tolerance: 0.5, Deviation: -1.5, OutsideSeconds: 330}},
+			want: "⚓ USDTUSD de-pegged to 0.985, -1.50% from 1 for 5m30s",
+		},
+		{
+			name: "Peg recovered",
+			ticker: &Ticker{Pair: "USDTUSD", AlertType: AlertPegRecovered,
+				Peg: &PegStatus{Target: 1, Tolerance: 0.5, OutsideSeconds: 330, Recovered: true}},
+			want: "⚓ USDTUSD back within 0.5% of its 1 peg after 5m30s",
+		},
+		{
+			name:   "Frozen quotes",
+			ticker: &Ticker{Pair: "BTCUSD", AlertType: AlertStale, Stale: &StaleStatus{Reason: StaleFrozen, Since: at.Add(-10 * time.Minute)}},
+			want:   "⏸ BTCUSD quotes from uphold unchanged for 10m0s",
+		},
+		{
+			name:   "No quotes",
+			ticker: &Ticker{Pair: "BTCUSD", AlertType: AlertStale, Stale: &StaleStatus{Reason: StaleNoData, Since: at.Add(-10 * time.Minute)}},
+			want:   "⏸ no BTCUSD quote from uphold for 10m0s",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.ticker.AlertTitle(at))
+		})
+	}
+}
+
+func TestAlertLimit(t *testing.T) {
+	tests := []struct {
+		name      string
+		ticker    *Ticker
+		wantName  string
+		wantValue string
+	}{
+		{
+			name:      "Fixed threshold",
+			ticker:    NewTicker("ETHEUR", 5, 1.5, 0),
+			wantName:  "Threshold",
+			wantValue: "1.5%",
+		},
+		{
+			name:      "Adaptive threshold before enough returns",
+			ticker:    &Ticker{Pair: "ETHEUR", Config: TickerConfig{PercOscillation: 1.5, ZScore: 3}},
+			wantName:  "Threshold",
+			wantValue: "1.5% (3σ)",
+		},
+		{
+			name:      "Level target",
+			ticker:    &Ticker{Pair: "BTCUSD", AlertType: AlertLevel, Level: &PriceLevel{Target: 60000, Direction: DirectionUp}},
+			wantName:  "Target",
+			wantValue: "60000",
+		},
+		{
+			name:      "Spread rule",
+			ticker:    &Ticker{Pair: "ETHEUR", AlertType: AlertSpread, Rule: &SpreadRule{MaxPct: 1}},
+			wantName:  "Rule",
+			wantValue: "spread > 1%",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name, value := tt.ticker.AlertLimit()
+
+			assert.Equal(t, tt.wantName, name)
+			assert.Equal(t, tt.wantValue, value)
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// DefaultPageLimit is the number of alerts of a page when no limit is given
+	DefaultPageLimit = 20
+	// MaxPageLimit bounds the number of alerts of a page
+	MaxPageLimit = 1000
+)
+
+// AlertFilter selects the stored alerts, zero fields don't filter. The alerts are selected from From included to To excluded,
+// and MinPercChange selects the alerts whose percentage change is at least that large on either direction
+type AlertFilter struct {
+	Pair          string
+	RuleType      AlertType
+	Direction     Direction
+	From          time.Time
+	To            time.Time
+	MinPercChange float64
+}
+
+// Validate checks the filter is consistent
+func (f AlertFilter) Validate() error {
+	if !f.From.IsZero() && !f.To.IsZero() && !f.From.Before(f.To) {
+		return errors.Errorf("from %s must be before to %s", f.From.Format(time.RFC3339), f.To.Format(time.RFC3339))
+	}
+
+	if f.MinPercChange < 0 {
+		return errors.Errorf("minimum percentage change can't be negative, got %v", f.MinPercChange)
+	}
+
+	switch f.Direction {
+	case "", DirectionUp, DirectionDown, DirectionNone:
+		return nil
+	default:
+		return errors.Errorf("unknown direction %q, use up, down or none", f.Direction)
+	}
+}
+
+// Page selects a page of results, the limit defaults to DefaultPageLimit and can't be above MaxPageLimit
+type Page struct {
+	Limit  int
+	Offset int
+}
+
+// Normalize returns the page with its default limit, failing on a negative offset or a limit above MaxPageLimit
+func (p Page) Normalize() (Page, error) {
+	if p.Limit < 0 || p.Limit > MaxPageLimit {
+		return Page{}, errors.Errorf("limit must be between 1 and %d, got %d", MaxPageLimit, p.Limit)
+	}
+
+	if p.Offset < 0 {
+		return Page{}, errors.Errorf("offset can't be negative, got %d", p.Offset)
+	}
+
+	if p.Limit == 0 {
+		p.Limit = DefaultPageLimit
+	}
+
+	return p, nil
+}
+
+// AlertPage is a page of the alerts matching a filter, newest first, along with the total count of matching alerts
+type AlertPage struct {
+	Alerts []Alert
+	Total  int
+	Page   Page
+}
+
+// HasMore checks if more alerts match the filter after the page
+func (p AlertPage) HasMore() bool {
+	return p.Page.Offset+len(p.Alerts) < p.Total
+}
+
+// BucketSize is the period alerts are aggregated over
+type BucketSize string
+
+const (
+	// BucketHour aggregates the alerts per hour
+	BucketHour BucketSize = "hour"
+	// BucketDay aggregates the alerts per day
+	BucketDay BucketSize = "day"
+)
+
+// ParseBucketSize parses a bucket size, an empty value is a day
+func ParseBucketSize(value string) (BucketSize, error) {
+	switch size := BucketSize(strings.ToLower(strings.TrimSpace(value))); size {
+	case "":
+		return BucketDay, nil
+	case BucketHour, BucketDay:
+		return size, nil
+	default:
+		return "", errors.Errorf("unknown bucket size %q, use hour or day", value)
+	}
+}
+
+// AlertBucket aggregates the alerts of a pair within the bucket starting at Start, MaxPercChange is the largest
+// percentage change of its alerts on either direction
+type AlertBucket struct {
+	Pair          string
+	Start         time.Time
+	Count         int
+	MaxPercChange float64
+}
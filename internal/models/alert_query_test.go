@@ -0,0 +1,99 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAlertFilterValidate(t *testing.T) {
+	from := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		filter      AlertFilter
+		errContains string
+	}{
+		{name: "Empty filter"},
+		{name: "Time range", filter: AlertFilter{From: from, To: from.Add(time.Hour), Direction: DirectionUp, MinPercChange: 2}},
+		{name: "From after to", filter: AlertFilter{From: from, To: from.Add(-time.Hour)}, errContains: "must be before"},
+		{name: "Empty time range", filter: AlertFilter{From: from, To: from}, errContains: "must be before"},
+		{name: "Negative minimum change", filter: AlertFilter{MinPercChange: -1}, errContains: "can't be negative"},
+		{name: "Unknown direction", filter: AlertFilter{Direction: "sideways"}, errContains: "unknown direction"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.filter.Validate()
+			if tt.errContains != "" {
+				assert.ErrorContains(t, err, tt.errContains)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestPageNormalize(t *testing.T) {
+	tests := []struct {
+		name     string
+		page     Page
+		wantPage Page
+		wantErr  bool
+	}{
+		{name: "Default limit", page: Page{Offset: 40}, wantPage: Page{Limit: DefaultPageLimit, Offset: 40}},
+		{name: "Given limit", page: Page{Limit: 5}, wantPage: Page{Limit: 5}},
+		{name: "Limit above maximum", page: Page{Limit: MaxPageLimit + 1}, wantErr: true},
+		{name: "Negative limit", page: Page{Limit: -1}, wantErr: true},
+		{name: "Negative offset", page: Page{Offset: -1}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := tt.page.Normalize()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantPage, page)
+		})
+	}
+}
+
+func TestAlertPageHasMore(t *testing.T) {
+	alerts := make([]Alert, 10)
+
+	assert.True(t, AlertPage{Alerts: alerts, Total: 25, Page: Page{Limit: 10, Offset: 10}}.HasMore())
+	assert.False(t, AlertPage{Alerts: alerts[:5], Total: 25, Page: Page{Limit: 10, Offset: 20}}.HasMore())
+	assert.False(t, AlertPage{Total: 0, Page: Page{Limit: 10}}.HasMore())
+}
+
+func TestParseBucketSize(t *testing.T) {
+	tests := []struct {
+		value    string
+		wantSize BucketSize
+		wantErr  bool
+	}{
+		{value: "", wantSize: BucketDay},
+		{value: "hour", wantSize: BucketHour},
+		{value: " Day ", wantSize: BucketDay},
+		{value: "week", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			size, err := ParseBucketSize(tt.value)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantSize, size)
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package models
+
+import (
+	"maps"
+	"time"
+)
+
+// AlertView is the snapshot of a ticker alert handed to the alert channels. It shares nothing with the ticker,
+// so the channels can read it while the scheduler keeps evaluating the ticker
+type AlertView struct {
+	Type        AlertType
+	Title       string
+	LimitName   string
+	Limit       string
+	Pair        string
+	Exchange    string
+	PriceAlert  bool
+	RuleAlert   bool
+	Rule        string
+	Direction   Direction
+	PriceSource PriceSource
+	Price       float64
+	Ask         float64
+	Bid         float64
+	Spread      float64
+	SpreadPct   float64
+	PriceChange float64
+	PercChange  float64
+	Threshold   float64
+	ZScore      float64
+	Target      float64
+	Divergence  *Divergence
+	Indicators  IndicatorValues
+	Peg         *PegStatus
+	Stale       *StaleStatus
+	Suppressed  int
+	Timestamp   time.Time
+}
+
+// NewAlertView builds the view of the ticker alert published at the given time, copying everything it refers to
+func NewAlertView(timestamp time.Time, ticker *Ticker) *AlertView {
+	alertType := ticker.AlertType
+	if alertType == "" {
+		alertType = AlertThreshold
+	}
+
+	direction := ticker.ChangeDirection()
+	limitName, limit := ticker.AlertLimit()
+
+	view := &AlertView{
+		Type:        alertType,
+		Title:       ticker.AlertTitle(timestamp),
+		LimitName:   limitName,
+		Limit:       limit,
+		Pair:        ticker.Pair,
+		Exchange:    ticker.ExchangeName(),
+		PriceAlert:  ticker.IsPriceAlert(),
+		RuleAlert:   ticker.IsRuleAlert(),
+		Rule:        ticker.RuleSource(),
+		Direction:   direction,
+		PriceSource: ticker.Config.PriceSource,
+		Price:       ticker.Price().Float64(),
+		Ask:         ticker.CurrentAsk.Float64(),
+		Bid:         ticker.CurrentBid.Float64(),
+		Spread:      ticker.Spread(),
+		SpreadPct:   ticker.SpreadPct(),
+		PriceChange: ticker.PriceChange,
+		PercChange:  ticker.PercChange,
+		Threshold:   ticker.Threshold(direction),
+		ZScore:      ticker.Config.ZScore,
+		Indicators:  maps.Clone(ticker.Indicators),
+		Suppressed:  ticker.Suppressed,
+		Timestamp:   timestamp.UTC(),
+	}
+
+	if ticker.Level != nil {
+		view.Target = ticker.Level.Target
+	}
+
+	if ticker.Divergence != nil {
+		divergence := *ticker.Divergence
+		view.Divergence = &divergence
+	}
+
+	if ticker.Peg != nil {
+		peg := *ticker.Peg
+		view.Peg = &peg
+	}
+
+	if ticker.Stale != nil {
+		stale := *ticker.Stale
+		view.Stale = &stale
+	}
+
+	return view
+}
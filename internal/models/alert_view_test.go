@@ -0,0 +1,36 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewAlertView(t *testing.T) {
+	at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.Local)
+
+	ticker := NewTicker("USDTUSD", 5, 1, 0)
+	ticker.CurrentAsk = 0.99
+	ticker.PreviousAsk = 1
+	ticker.IsAbovePercOscillation()
+	ticker.Indicators = IndicatorValues{"RSI(14)": 72.5}
+	ticker.Peg = &PegStatus{Target: 1, Tolerance: 0.5, Deviation: -1}
+
+	view := NewAlertView(at, ticker)
+
+	ticker.CurrentAsk = 1
+	ticker.Indicators["RSI(14)"] = 30
+	ticker.Peg.Deviation = 0
+
+	assert.Equal(t, AlertThreshold, view.Type)
+	assert.Equal(t, "▼ USDTUSD -1.00%", view.Title)
+	assert.Equal(t, "Threshold", view.LimitName)
+	assert.Equal(t, "1%", view.Limit)
+	assert.Equal(t, DirectionDown, view.Direction)
+	assert.Equal(t, 0.99, view.Price)
+	assert.Equal(t, 1.0, view.Threshold)
+	assert.Equal(t, IndicatorValues{"RSI(14)": 72.5}, view.Indicators)
+	assert.Equal(t, -1.0, view.Peg.Deviation)
+	assert.Equal(t, at.UTC(), view.Timestamp)
+}
@@ -0,0 +1,171 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// QuoteBoard holds the latest quote of every ticker sharing its quotes, safe for concurrent use by their schedulers
+type QuoteBoard struct {
+	mu     sync.RWMutex
+	quotes map[string]Quote
+}
+
+// NewQuoteBoard returns a new instance of QuoteBoard
+func NewQuoteBoard() *QuoteBoard {
+	return &QuoteBoard{quotes: make(map[string]Quote)}
+}
+
+// boardKey identifies the quotes of a pair on an exchange, whatever the pair notation
+func boardKey(exchange, pair string) string {
+	if exchange == "" {
+		exchange = DefaultExchange
+	}
+
+	return exchange + ":" + CompactPair(pair)
+}
+
+// Update sets the latest quote of the pair on the exchange
+func (b *QuoteBoard) Update(exchange, pair string, quote Quote) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.quotes[boardKey(exchange, pair)] = quote
+}
+
+// Latest returns the latest quote of the pair on the exchange, false when none was shared yet
+func (b *QuoteBoard) Latest(exchange, pair string) (Quote, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	quote, ok := b.quotes[boardKey(exchange, pair)]
+
+	return quote, ok
+}
+
+// ArbitrageLeg is a pair on an exchange watched by an arbitrage rule
+type ArbitrageLeg struct {
+	Exchange string `yaml:"exchange" json:"exchange"`
+	Pair     string `yaml:"pair" json:"pair"`
+}
+
+func (l ArbitrageLeg) String() string {
+	exchange := l.Exchange
+	if exchange == "" {
+		exchange = DefaultExchange
+	}
+
+	return l.Pair + "@" + exchange
+}
+
+// Divergence is the arbitrage opportunity found between two legs, buying at the ask of one and selling at the bid of the other
+type Divergence struct {
+	Buy    ArbitrageLeg `json:"buy"`
+	Ask    float64      `json:"ask"`
+	Sell   ArbitrageLeg `json:"sell"`
+	Bid    float64      `json:"bid"`
+	Gross  float64      `json:"gross_pct"`
+	Net    float64      `json:"net_pct"`
+	FeePct float64      `json:"fee_pct"`
+}
+
+// ArbitrageRule fires when the best bid of a leg exceeds the best ask of another by more than the threshold percentage,
+// after subtracting the fee percentage. Quotes older than MaxAge are ignored, and the rule fires once per opportunity,
+// re-arming when the divergence closes. The rule is shared by the tickers of its legs, firing on whichever sees it first
+type ArbitrageRule struct {
+	Board     *QuoteBoard
+	Legs      []ArbitrageLeg
+	Threshold float64
+	Fee       float64
+	MaxAge    time.Duration
+
+	mu   sync.Mutex
+	open bool
+}
+
+// Type returns AlertArbitrage
+func (r *ArbitrageRule) Type() AlertType {
+	return AlertArbitrage
+}
+
+// Evaluate checks if the legs diverge beyond the threshold, setting the opportunity found on the ticker Divergence when it fires
+func (r *ArbitrageRule) Evaluate(ticker *Ticker, at time.Time) (bool, error) {
+	divergence, found := r.divergence(at)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !found || divergence.Net < r.Threshold {
+		r.open = false
+		return false, nil
+	}
+
+	if r.open {
+		return false, nil
+	}
+
+	r.open = true
+
+	ticker.Divergence = &divergence
+	ticker.PriceChange = divergence.Bid - divergence.Ask
+	ticker.PercChange = divergence.Net
+
+	return true, nil
+}
+
+// divergence returns the widest divergence between the fresh quotes of two different legs, buying at the ask of one and selling at the bid of the other
+func (r *ArbitrageRule) divergence(at time.Time) (Divergence, bool) {
+	legs := make([]ArbitrageLeg, 0, len(r.Legs))
+	quotes := make([]Quote, 0, len(r.Legs))
+
+	for _, leg := range r.Legs {
+		quote, ok := r.Board.Latest(leg.Exchange, leg.Pair)
+		if !ok || quote.Ask == 0 || quote.Bid == 0 || (r.MaxAge > 0 && at.Sub(quote.Time) > r.MaxAge) {
+			continue
+		}
+
+		legs = append(legs, leg)
+		quotes = append(quotes, quote)
+	}
+
+	var best Divergence
+	found := false
+
+	for i := range legs {
+		for j := range legs {
+			if i == j {
+				continue
+			}
+
+			ask, bid := quotes[i].Ask.Float64(), quotes[j].Bid.Float64()
+			gross := (bid - ask) / ask * 100
+
+			if !found || gross > best.Gross {
+				best = Divergence{Buy: legs[i], Ask: ask, Sell: legs[j], Bid: bid, Gross: gross, Net: gross - r.Fee, FeePct: r.Fee}
+				found = true
+			}
+		}
+	}
+
+	return best, found
+}
+
+func (r *ArbitrageRule) String() string {
+	legs := make([]string, 0, len(r.Legs))
+	for _, leg := range r.Legs {
+		legs = append(legs, leg.String())
+	}
+
+	return fmt.Sprintf("divergence %s > %g%% after %g%% fee", strings.Join(legs, " / "), r.Threshold, r.Fee)
+}
+
+// ShareQuote shares the current quote observed at the given time on the ticker quote board, when it has one
+func (t *Ticker) ShareQuote(at time.Time) {
+	if t.Board == nil {
+		return
+	}
+
+	t.Board.Update(t.ExchangeName(), t.Pair, Quote{Ask: t.CurrentAsk, Bid: t.CurrentBid, Last: t.CurrentLast, Time: at})
+}
@@ -0,0 +1,78 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestArbitrageRule(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	board := NewQuoteBoard()
+	rule := &ArbitrageRule{
+		Board:     board,
+		Legs:      []ArbitrageLeg{{Exchange: "uphold", Pair: "BTC-USD"}, {Exchange: "kraken", Pair: "XBTUSD"}, {Exchange: "coinbase", Pair: "BTC-USD"}},
+		Threshold: 1,
+		Fee:       0.5,
+		MaxAge:    time.Minute,
+	}
+
+	uphold := NewTicker("BTC-USD", 10, 5, 0)
+	uphold.Config.Rules = []Rule{rule}
+
+	share := func(exchange, pair string, ask, bid Float64, at time.Time) {
+		board.Update(exchange, pair, Quote{Ask: ask, Bid: bid, Time: at})
+	}
+
+	share("uphold", "BTCUSD", 100, 99.9, start)
+	share("kraken", "XBTUSD", 101.2, 101, start)
+
+	fired, err := rule.Evaluate(uphold, start)
+	assert.NoError(t, err)
+	assert.False(t, fired, "1% gross is 0.5% after fees")
+
+	share("coinbase", "BTC-USD", 102, 101.8, start)
+
+	fired, _ = rule.Evaluate(uphold, start)
+	assert.True(t, fired)
+	assert.Equal(t, &Divergence{
+		Buy:    ArbitrageLeg{Exchange: "uphold", Pair: "BTC-USD"},
+		Ask:    100,
+		Sell:   ArbitrageLeg{Exchange: "coinbase", Pair: "BTC-USD"},
+		Bid:    101.8,
+		Gross:  1.7999999999999972,
+		Net:    1.2999999999999972,
+		FeePct: 0.5,
+	}, uphold.Divergence)
+	assert.Equal(t, AlertArbitrage, rule.Type())
+
+	fired, _ = rule.Evaluate(uphold, start.Add(time.Second))
+	assert.False(t, fired, "fires once per opportunity")
+
+	fired, _ = rule.Evaluate(uphold, start.Add(2*time.Minute))
+	assert.False(t, fired, "stale quotes are ignored")
+
+	share("uphold", "BTC-USD", 100, 99.9, start.Add(2*time.Minute))
+	share("coinbase", "BTC-USD", 102, 101.8, start.Add(2*time.Minute))
+
+	fired, _ = rule.Evaluate(uphold, start.Add(2*time.Minute))
+	assert.True(t, fired, "re-armed once the divergence closed")
+}
+
+func TestArbitrageRuleSameLeg(t *testing.T) {
+	board := NewQuoteBoard()
+	board.Update("uphold", "BTC-USD", Quote{Ask: 100, Bid: 99})
+	board.Update("kraken", "BTC-USD", Quote{Ask: 98, Bid: 97})
+
+	rule := &ArbitrageRule{Board: board, Legs: []ArbitrageLeg{{Exchange: "uphold", Pair: "BTC-USD"}, {Exchange: "kraken", Pair: "BTC-USD"}}, Threshold: 1}
+
+	ticker := NewTicker("BTC-USD", 10, 5, 0)
+
+	fired, _ := rule.Evaluate(ticker, time.Now())
+	assert.True(t, fired, "buys on the cheapest ask and sells on the other leg bid")
+	assert.Equal(t, "kraken", ticker.Divergence.Buy.Exchange)
+	assert.Equal(t, 99.0, ticker.Divergence.Bid)
+	assert.Equal(t, "divergence BTC-USD@uphold / BTC-USD@kraken > 1% after 0% fee", rule.String())
+}
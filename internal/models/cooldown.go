@@ -0,0 +1,82 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// alertMark records when, at which price and on which direction an alert last fired,
+// and if the price retreated enough since then to re-arm it
+type alertMark struct {
+	at        time.Time
+	price     float64
+	direction Direction
+	armed     bool
+}
+
+// alertKey identifies the alerts considered the same by the cooldown and re-arm rules
+func (t *Ticker) alertKey() string {
+	if t.AlertType == AlertLevel && t.Level != nil {
+		return fmt.Sprintf("%s:%s:%g", t.AlertType, t.Level.Direction, t.Level.Target)
+	}
+
+	if t.AlertType != AlertThreshold && t.Rule != nil {
+		return fmt.Sprintf("%s:%s", t.AlertType, t.Rule)
+	}
+
+	return fmt.Sprintf("%s:%s", t.AlertType, t.ChangeDirection())
+}
+
+// RearmAlerts re-arms the fired alerts the current price retreated from by at least the re-arm percentage,
+// falling below the alerted price for rises, rising above it for drops and moving either way for expressions
+func (t *Ticker) RearmAlerts() {
+	price := t.Price().Float64()
+	if price == 0 || t.Config.Rearm <= 0 {
+		return
+	}
+
+	for key, mark := range t.alertMarks {
+		if mark.armed {
+			continue
+		}
+
+		retreat := (mark.price - price) / mark.price * 100
+		switch mark.direction {
+		case DirectionDown:
+			retreat = -retreat
+		case DirectionNone:
+			retreat = math.Abs(retreat)
+		}
+
+		if retreat >= t.Config.Rearm {
+			mark.armed = true
+			t.alertMarks[key] = mark
+		}
+	}
+}
+
+// ShouldAlert checks if the current alert can fire at the given time, it's suppressed while the same alert is cooling down
+// or waiting to be re-armed. Suppressed alerts are counted on Suppressed, and an alert that fires marks the start of its cooldown
+func (t *Ticker) ShouldAlert(at time.Time) bool {
+	key := t.alertKey()
+
+	mark, fired := t.alertMarks[key]
+	if fired {
+		coolingDown := t.Config.Cooldown > 0 && at.Sub(mark.at) < t.Config.Cooldown
+		disarmed := t.Config.Rearm > 0 && !mark.armed
+
+		if coolingDown || disarmed {
+			t.Suppressed++
+			return false
+		}
+	}
+
+	if t.alertMarks == nil {
+		t.alertMarks = make(map[string]alertMark)
+	}
+
+	t.alertMarks[key] = alertMark{at: at, price: t.Price().Float64(), direction: t.ChangeDirection()}
+
+	return true
+}
@@ -0,0 +1,95 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShouldAlert(t *testing.T) {
+	type step struct {
+		after time.Duration
+		ask   Float64
+		up    bool
+		want  bool
+	}
+
+	tests := []struct {
+		name           string
+		cooldown       time.Duration
+		rearm          float64
+		steps          []step
+		wantSuppressed int
+	}{
+		{
+			name:  "Every alert fires without cooldown nor re-arm",
+			steps: []step{{ask: 105, up: true, want: true}, {after: time.Second, ask: 110, up: true, want: true}},
+		},
+		{
+			name:     "Same alert is suppressed while cooling down",
+			cooldown: 10 * time.Minute,
+			steps: []step{
+				{ask: 105, up: true, want: true},
+				{after: time.Minute, ask: 110, up: true, want: false},
+				{after: 2 * time.Minute, ask: 115, up: true, want: false},
+				{after: 3 * time.Minute, ask: 100, up: false, want: true},
+				{after: 10 * time.Minute, ask: 120, up: true, want: true},
+			},
+			wantSuppressed: 2,
+		},
+		{
+			name:  "Same alert fires again once the price retreats by the re-arm percentage",
+			rearm: 2,
+			steps: []step{
+				{ask: 100, up: true, want: true},
+				{after: time.Minute, ask: 99, up: true, want: false},
+				{after: 2 * time.Minute, ask: 98, up: true, want: true},
+				{after: 3 * time.Minute, ask: 99, up: true, want: false},
+			},
+			wantSuppressed: 2,
+		},
+	}
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ticker := NewTicker("BTCUSD", 60, 5.0, 0)
+			ticker.Config.Cooldown = tt.cooldown
+			ticker.Config.Rearm = tt.rearm
+			ticker.AlertType = AlertThreshold
+
+			for _, step := range tt.steps {
+				ticker.CurrentAsk = step.ask
+				ticker.PriceChange = -1
+				if step.up {
+					ticker.PriceChange = 1
+				}
+
+				ticker.RearmAlerts()
+				assert.Equal(t, step.want, ticker.ShouldAlert(start.Add(step.after)), "ask %v", step.ask)
+			}
+
+			assert.Equal(t, tt.wantSuppressed, ticker.Suppressed)
+		})
+	}
+}
+
+func TestShouldAlertLevels(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	ticker := NewTicker("BTCUSD", 60, 5.0, 0)
+	ticker.Config.Cooldown = time.Hour
+	ticker.CurrentAsk = 70000
+	ticker.AlertType = AlertLevel
+
+	ticker.Level = &PriceLevel{Target: 70000, Direction: DirectionUp}
+	assert.True(t, ticker.ShouldAlert(start))
+
+	ticker.Level = &PriceLevel{Target: 69000, Direction: DirectionUp}
+	assert.True(t, ticker.ShouldAlert(start), "each level cools down on its own")
+
+	ticker.Level = &PriceLevel{Target: 70000, Direction: DirectionUp}
+	assert.False(t, ticker.ShouldAlert(start.Add(time.Minute)))
+}
@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// DeliveryStatus is the outcome of delivering an alert to an external channel
+type DeliveryStatus string
+
+const (
+	// DeliveryDelivered is recorded when the channel accepted the alert
+	DeliveryDelivered DeliveryStatus = "delivered"
+	// DeliveryFailed is recorded when every attempt to deliver the alert failed
+	DeliveryFailed DeliveryStatus = "failed"
+)
+
+// Delivery represents the attempt of delivering an alert to an external channel
+type Delivery struct {
+	Endpoint   string
+	Pair       string
+	AlertType  AlertType
+	Status     DeliveryStatus
+	Attempts   int
+	StatusCode int
+	Error      string
+	Timestamp  time.Time
+}
@@ -0,0 +1,38 @@
+package models
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Direction is the direction of a price change a ticker alerts on
+type Direction string
+
+const (
+	// DirectionBoth alerts on rises and drops
+	DirectionBoth Direction = "both"
+	// DirectionUp alerts on rises only
+	DirectionUp Direction = "up"
+	// DirectionDown alerts on drops only
+	DirectionDown Direction = "down"
+	// DirectionNone doesn't alert on price changes
+	DirectionNone Direction = "none"
+)
+
+// ParseDirection parses a direction name, an empty one meaning both directions
+func ParseDirection(s string) (Direction, error) {
+	switch direction := Direction(strings.ToLower(strings.TrimSpace(s))); direction {
+	case "":
+		return DirectionBoth, nil
+	case DirectionBoth, DirectionUp, DirectionDown, DirectionNone:
+		return direction, nil
+	default:
+		return "", errors.Errorf("unknown direction %q, use up, down, both or none", s)
+	}
+}
+
+// Allows checks if changes on the given direction are alerted
+func (d Direction) Allows(change Direction) bool {
+	return d == "" || d == DirectionBoth || d == change
+}
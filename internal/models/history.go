@@ -0,0 +1,93 @@
+package models
+
+import "time"
+
+// maxHistoryQuotes bounds the quotes recorded per ticker, once reached the oldest quotes are overwritten
+const maxHistoryQuotes = 4096
+
+// series are the price series rule expressions are written over, computed from each quote
+var series = map[string]func(source PriceSource, quote Quote) float64{
+	"ask":  func(_ PriceSource, quote Quote) float64 { return quote.Ask.Float64() },
+	"bid":  func(_ PriceSource, quote Quote) float64 { return quote.Bid.Float64() },
+	"last": func(_ PriceSource, quote Quote) float64 { return quote.Last.Float64() },
+	"mid": func(_ PriceSource, quote Quote) float64 {
+		return PriceMid.price(quote.Ask, quote.Bid, quote.Last).Float64()
+	},
+	"price": func(source PriceSource, quote Quote) float64 {
+		return source.price(quote.Ask, quote.Bid, quote.Last).Float64()
+	},
+	"spread": func(_ PriceSource, quote Quote) float64 {
+		spread, _ := spreadOf(quote.Ask, quote.Bid)
+		return spread
+	},
+	"spread_pct": func(_ PriceSource, quote Quote) float64 {
+		_, pct := spreadOf(quote.Ask, quote.Bid)
+		return pct
+	},
+}
+
+// quoteHistory holds the quotes recorded within the longest lookback of the ticker rules, oldest first
+type quoteHistory struct {
+	ring[Quote]
+}
+
+// lookback returns the longest quote history read by the rules
+func (c TickerConfig) lookback() time.Duration {
+	var lookback time.Duration
+
+	for _, rule := range c.Rules {
+		if reader, ok := rule.(interface{ Lookback() time.Duration }); ok {
+			lookback = max(lookback, reader.Lookback())
+		}
+	}
+
+	return lookback
+}
+
+// RecordQuote records the current quote observed at the given time for the rules reading the quote history,
+// dropping the quotes older than their longest lookback
+func (t *Ticker) RecordQuote(at time.Time) {
+	lookback := t.Config.lookback()
+	if lookback == 0 {
+		return
+	}
+
+	t.quotes.limit = maxHistoryQuotes
+	t.quotes.push(Quote{Ask: t.CurrentAsk, Bid: t.CurrentBid, Last: t.CurrentLast, Time: at})
+
+	for t.quotes.size > 0 && t.quotes.at(0).Time.Before(at.Add(-lookback)) {
+		t.quotes.dropOldest()
+	}
+}
+
+// quoteEnv evaluates rule expressions on the ticker quotes
+type quoteEnv struct {
+	ticker *Ticker
+	at     time.Time
+}
+
+// Current returns the series value of the current ticker quote
+func (e quoteEnv) Current(name string) float64 {
+	quote := Quote{Ask: e.ticker.CurrentAsk, Bid: e.ticker.CurrentBid, Last: e.ticker.CurrentLast, Time: e.at}
+
+	return series[name](e.ticker.Config.PriceSource, quote)
+}
+
+// Since returns the series values of the quotes recorded within the lookback, ending with the current quote
+func (e quoteEnv) Since(name string, lookback time.Duration) []float64 {
+	var values []float64
+
+	history := &e.ticker.quotes
+	for i := 0; i < history.size; i++ {
+		quote := history.at(i)
+		if !quote.Time.Before(e.at.Add(-lookback)) {
+			values = append(values, series[name](e.ticker.Config.PriceSource, quote))
+		}
+	}
+
+	if len(values) == 0 {
+		values = append(values, e.Current(name))
+	}
+
+	return values
+}
@@ -0,0 +1,423 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// maxIndicatorBars bounds the bars kept per ticker, once reached the oldest bars are overwritten
+const maxIndicatorBars = 1024
+
+// maxIndicatorPeriod bounds the period of an indicator, leaving the averages enough bars to settle
+const maxIndicatorPeriod = 500
+
+// defaultBandWidth is the number of standard deviations of the Bollinger bands when not given
+const defaultBandWidth = 2
+
+// IndicatorKind represents a technical indicator computed over the closing prices of the ticker bars
+type IndicatorKind string
+
+const (
+	// IndicatorPrice is the current price of the ticker price source
+	IndicatorPrice IndicatorKind = "price"
+	// IndicatorSMA is the simple moving average of the closes
+	IndicatorSMA IndicatorKind = "sma"
+	// IndicatorEMA is the exponential moving average of the closes, seeded with their simple moving average
+	IndicatorEMA IndicatorKind = "ema"
+	// IndicatorRSI is the relative strength index of the closes, with Wilder's smoothing
+	IndicatorRSI IndicatorKind = "rsi"
+	// IndicatorBBUpper is the upper Bollinger band, the simple moving average plus a number of standard deviations
+	IndicatorBBUpper IndicatorKind = "bb_upper"
+	// IndicatorBBLower is the lower Bollinger band, the simple moving average minus a number of standard deviations
+	IndicatorBBLower IndicatorKind = "bb_lower"
+)
+
+// Indicator is a technical indicator over a number of bars, Width is the number of standard deviations of the Bollinger bands
+type Indicator struct {
+	Kind   IndicatorKind
+	Period int
+	Width  float64
+}
+
+var indicatorPattern = regexp.MustCompile(`^([a-z_]+)\s*\(\s*(\d+)\s*(?:,\s*([0-9.]+)\s*)?\)$`)
+
+// ParseIndicator parses an indicator such as ema(12) or bb_upper(20, 2), case insensitive
+func ParseIndicator(value string) (Indicator, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == string(IndicatorPrice) {
+		return Indicator{Kind: IndicatorPrice}, nil
+	}
+
+	match := indicatorPattern.FindStringSubmatch(value)
+	if match == nil {
+		return Indicator{}, errors.Errorf("invalid indicator %q, use a call such as ema(12)", value)
+	}
+
+	indicator := Indicator{Kind: IndicatorKind(match[1])}
+	indicator.Period, _ = strconv.Atoi(match[2])
+
+	switch indicator.Kind {
+	case IndicatorSMA, IndicatorEMA, IndicatorRSI:
+		if match[3] != "" {
+			return Indicator{}, errors.Errorf("%s takes a single period", indicator.Kind)
+		}
+	case IndicatorBBUpper, IndicatorBBLower:
+		indicator.Width = defaultBandWidth
+
+		if match[3] != "" {
+			width, err := strconv.ParseFloat(match[3], 64)
+			if err != nil || width <= 0 {
+				return Indicator{}, errors.Errorf("%s width must be positive, got %s", indicator.Kind, match[3])
+			}
+
+			indicator.Width = width
+		}
+	default:
+		return Indicator{}, errors.Errorf("unknown indicator %q, use sma, ema, rsi, bb_upper or bb_lower", indicator.Kind)
+	}
+
+	if indicator.Period < 2 || indicator.Period > maxIndicatorPeriod {
+		return Indicator{}, errors.Errorf("%s period must be between 2 and %d, got %d", indicator.Kind, maxIndicatorPeriod, indicator.Period)
+	}
+
+	return indicator, nil
+}
+
+// Compute returns the indicator value over the closes, oldest first, false while there are too few closes
+func (i Indicator) Compute(closes []float64) (float64, bool) {
+	need := i.Period
+	if i.Kind == IndicatorPrice {
+		need = 1
+	} else if i.Kind == IndicatorRSI {
+		need = i.Period + 1
+	}
+
+	if len(closes) < need {
+		return 0, false
+	}
+
+	switch i.Kind {
+	case IndicatorSMA:
+		return mean(closes[len(closes)-i.Period:]), true
+	case IndicatorEMA:
+		return ema(closes, i.Period), true
+	case IndicatorRSI:
+		return rsi(closes, i.Period), true
+	case IndicatorBBUpper, IndicatorBBLower:
+		window := closes[len(closes)-i.Period:]
+		average, deviation := mean(window), stddev(window)
+
+		if i.Kind == IndicatorBBLower {
+			return average - i.Width*deviation, true
+		}
+
+		return average + i.Width*deviation, true
+	default:
+		return closes[len(closes)-1], true
+	}
+}
+
+func (i Indicator) String() string {
+	switch i.Kind {
+	case IndicatorPrice:
+		return string(IndicatorPrice)
+	case IndicatorBBUpper, IndicatorBBLower:
+		return fmt.Sprintf("%s(%d, %g)", strings.ToUpper(string(i.Kind)), i.Period, i.Width)
+	default:
+		return fmt.Sprintf("%s(%d)", strings.ToUpper(string(i.Kind)), i.Period)
+	}
+}
+
+// mean returns the arithmetic mean of the values
+func mean(values []float64) float64 {
+	var sum float64
+	for _, value := range values {
+		sum += value
+	}
+
+	return sum / float64(len(values))
+}
+
+// stddev returns the population standard deviation of the values
+func stddev(values []float64) float64 {
+	average := mean(values)
+
+	var sum float64
+	for _, value := range values {
+		sum += (value - average) * (value - average)
+	}
+
+	return math.Sqrt(sum / float64(len(values)))
+}
+
+// ema returns the exponential moving average of the closes, seeded with the simple moving average of the oldest period
+func ema(closes []float64, period int) float64 {
+	alpha := 2 / float64(period+1)
+
+	value := mean(closes[:period])
+	for _, price := range closes[period:] {
+		value += alpha * (price - value)
+	}
+
+	return value
+}
+
+// rsi returns the relative strength index of the closes, averaging gains and losses with Wilder's smoothing
+func rsi(closes []float64, period int) float64 {
+	var gain, loss float64
+
+	for i := 1; i < len(closes); i++ {
+		change := closes[i] - closes[i-1]
+		up, down := max(change, 0), max(-change, 0)
+
+		if i <= period {
+			gain += up / float64(period)
+			loss += down / float64(period)
+
+			continue
+		}
+
+		gain = (gain*float64(period-1) + up) / float64(period)
+		loss = (loss*float64(period-1) + down) / float64(period)
+	}
+
+	if loss == 0 {
+		if gain == 0 {
+			return 50
+		}
+
+		return 100
+	}
+
+	return 100 - 100/(1+gain/loss)
+}
+
+// IndicatorValues are the indicator values an alert fired on, by indicator
+type IndicatorValues map[string]float64
+
+func (v IndicatorValues) String() string {
+	names := make([]string, 0, len(v))
+	for name := range v {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	values := make([]string, 0, len(names))
+	for _, name := range names {
+		values = append(values, fmt.Sprintf("%s=%.8g", name, v[name]))
+	}
+
+	return strings.Join(values, ", ")
+}
+
+// bar is the last price observed within an interval starting at the given time
+type bar struct {
+	start time.Time
+	close float64
+}
+
+// indicatorBars holds the bars indicators are computed over, oldest first
+type indicatorBars struct {
+	ring[bar]
+}
+
+// closes returns the closing prices of the bars, oldest first
+func (b *indicatorBars) closes() []float64 {
+	closes := make([]float64, b.size)
+	for i := range closes {
+		closes[i] = b.at(i).close
+	}
+
+	return closes
+}
+
+// RecordBar records the current price observed at the given time for the indicator rules, closing a bar per configured
+// indicator interval, or per price without one. The newest bar is still forming and moves with every price of its interval
+func (t *Ticker) RecordBar(at time.Time) {
+	price := t.Price().Float64()
+	if price == 0 || !t.Config.hasIndicators() {
+		return
+	}
+
+	start := at
+	if t.Config.IndicatorInterval > 0 {
+		start = at.Truncate(t.Config.IndicatorInterval)
+	}
+
+	if t.bars.size > 0 && t.Config.IndicatorInterval > 0 && t.bars.newest().start.Equal(start) {
+		t.bars.newest().close = price
+		return
+	}
+
+	t.bars.limit = maxIndicatorBars
+	t.bars.push(bar{start: start, close: price})
+}
+
+// hasIndicators checks if any of the rules reads the indicators
+func (c TickerConfig) hasIndicators() bool {
+	for _, rule := range c.Rules {
+		if _, ok := rule.(*IndicatorRule); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// indicatorOperand is a side of an indicator rule, an indicator or a constant when nil
+type indicatorOperand struct {
+	indicator *Indicator
+	value     float64
+}
+
+// parseIndicatorOperand parses an indicator or a number
+func parseIndicatorOperand(value string) (indicatorOperand, error) {
+	if number, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+		return indicatorOperand{value: number}, nil
+	}
+
+	indicator, err := ParseIndicator(value)
+	if err != nil {
+		return indicatorOperand{}, err
+	}
+
+	return indicatorOperand{indicator: &indicator}, nil
+}
+
+// compute returns the value of the operand over the closes, false while the indicator lacks closes
+func (o indicatorOperand) compute(closes []float64) (float64, bool) {
+	if o.indicator == nil {
+		return o.value, true
+	}
+
+	return o.indicator.Compute(closes)
+}
+
+func (o indicatorOperand) String() string {
+	if o.indicator == nil {
+		return strconv.FormatFloat(o.value, 'g', -1, 64)
+	}
+
+	return o.indicator.String()
+}
+
+var indicatorRulePattern = regexp.MustCompile(`^(.+?)\s*(>=|<=|>|<|\bcrosses(?:\s+(?:above|below))?\b)\s*(.+)$`)
+
+// IndicatorRule fires when an indicator compares to another indicator or a number, such as rsi(14) > 70,
+// or crosses it, such as ema(12) crosses ema(26). It fires once per crossing, and comparisons fire once
+// when they start to hold and re-arm when they stop holding
+type IndicatorRule struct {
+	left     indicatorOperand
+	operator string
+	right    indicatorOperand
+
+	side    int
+	holding bool
+}
+
+// NewIndicatorRule parses the condition into a rule, the operator is one of > >= < <=, crosses, crosses above or crosses below
+func NewIndicatorRule(source string) (*IndicatorRule, error) {
+	match := indicatorRulePattern.FindStringSubmatch(strings.ToLower(strings.TrimSpace(source)))
+	if match == nil {
+		return nil, errors.Errorf("invalid indicator rule %q, use a condition such as rsi(14) > 70 or ema(12) crosses ema(26)", source)
+	}
+
+	left, err := parseIndicatorOperand(match[1])
+	if err != nil {
+		return nil, err
+	}
+
+	right, err := parseIndicatorOperand(match[3])
+	if err != nil {
+		return nil, err
+	}
+
+	if left.indicator == nil && right.indicator == nil {
+		return nil, errors.Errorf("indicator rule %q compares two numbers", source)
+	}
+
+	return &IndicatorRule{left: left, operator: strings.Join(strings.Fields(match[2]), " "), right: right}, nil
+}
+
+// Type returns AlertIndicator
+func (r *IndicatorRule) Type() AlertType {
+	return AlertIndicator
+}
+
+// Evaluate checks the condition on the ticker bars, setting the indicator values on the ticker Indicators when it fires
+func (r *IndicatorRule) Evaluate(ticker *Ticker, _ time.Time) (bool, error) {
+	closes := ticker.bars.closes()
+
+	left, leftOk := r.left.compute(closes)
+	right, rightOk := r.right.compute(closes)
+
+	if !leftOk || !rightOk {
+		return false, nil
+	}
+
+	fired := false
+
+	switch r.operator {
+	case "crosses", "crosses above", "crosses below":
+		side := 0
+		if left > right {
+			side = 1
+		} else if left < right {
+			side = -1
+		}
+
+		crossedAbove := r.side < 0 && side > 0
+		crossedBelow := r.side > 0 && side < 0
+
+		fired = (crossedAbove && r.operator != "crosses below") || (crossedBelow && r.operator != "crosses above")
+
+		if side != 0 {
+			r.side = side
+		}
+	default:
+		holds := compare(left, r.operator, right)
+		fired = holds && !r.holding
+		r.holding = holds
+	}
+
+	if !fired {
+		return false, nil
+	}
+
+	values := IndicatorValues{}
+	for _, operand := range []indicatorOperand{r.left, r.right} {
+		if operand.indicator != nil {
+			values[operand.String()], _ = operand.compute(closes)
+		}
+	}
+
+	ticker.Indicators = values
+
+	return true, nil
+}
+
+// compare applies the comparison operator
+func compare(left float64, operator string, right float64) bool {
+	switch operator {
+	case ">":
+		return left > right
+	case ">=":
+		return left >= right
+	case "<":
+		return left < right
+	default:
+		return left <= right
+	}
+}
+
+func (r *IndicatorRule) String() string {
+	return fmt.Sprintf("%s %s %s", r.left, r.operator, r.right)
+}
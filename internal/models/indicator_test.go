@@ -0,0 +1,160 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIndicatorCompute(t *testing.T) {
+	rising := []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+
+	tests := []struct {
+		name      string
+		indicator Indicator
+		closes    []float64
+		want      float64
+		wantOk    bool
+	}{
+		{name: "Price", indicator: Indicator{Kind: IndicatorPrice}, closes: rising, want: 10, wantOk: true},
+		{name: "SMA", indicator: Indicator{Kind: IndicatorSMA, Period: 3}, closes: rising, want: 9, wantOk: true},
+		{name: "SMA warming up", indicator: Indicator{Kind: IndicatorSMA, Period: 3}, closes: rising[:2]},
+		{name: "EMA seeded with the SMA", indicator: Indicator{Kind: IndicatorEMA, Period: 3}, closes: rising, want: 9, wantOk: true},
+		{name: "EMA of a single period", indicator: Indicator{Kind: IndicatorEMA, Period: 3}, closes: []float64{3, 6, 12}, want: 7, wantOk: true},
+		{name: "RSI without losses", indicator: Indicator{Kind: IndicatorRSI, Period: 3}, closes: rising, want: 100, wantOk: true},
+		{name: "RSI without changes", indicator: Indicator{Kind: IndicatorRSI, Period: 2}, closes: []float64{5, 5, 5}, want: 50, wantOk: true},
+		{name: "RSI with Wilder's smoothing", indicator: Indicator{Kind: IndicatorRSI, Period: 2}, closes: []float64{10, 11, 10, 12}, want: 83.33333333333333, wantOk: true},
+		{name: "RSI needs a change per period", indicator: Indicator{Kind: IndicatorRSI, Period: 3}, closes: rising[:3]},
+		{name: "Upper Bollinger band", indicator: Indicator{Kind: IndicatorBBUpper, Period: 4, Width: 2}, closes: rising, want: 10.73606797749979, wantOk: true},
+		{name: "Lower Bollinger band", indicator: Indicator{Kind: IndicatorBBLower, Period: 4, Width: 1}, closes: rising, want: 7.381966011250105, wantOk: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.indicator.Compute(tt.closes)
+
+			assert.Equal(t, tt.wantOk, ok)
+			assert.InDelta(t, tt.want, got, 1e-9)
+		})
+	}
+}
+
+func TestNewIndicatorRule(t *testing.T) {
+	tests := []struct {
+		source  string
+		want    string
+		wantErr string
+	}{
+		{source: "RSI(14) > 70", want: "RSI(14) > 70"},
+		{source: "ema(12)  crosses   above EMA(26)", want: "EMA(12) crosses above EMA(26)"},
+		{source: "price>bb_upper(20)", want: "price > BB_UPPER(20, 2)"},
+		{source: "sma(50) crosses below 30000.5", want: "SMA(50) crosses below 30000.5"},
+		{source: "macd(12) > 0", wantErr: `unknown indicator "macd", use sma, ema, rsi, bb_upper or bb_lower`},
+		{source: "rsi(1) > 70", wantErr: "rsi period must be between 2 and 500, got 1"},
+		{source: "rsi(14, 2) > 70", wantErr: "rsi takes a single period"},
+		{source: "bb_lower(20, 0) < price", wantErr: "bb_lower width must be positive, got 0"},
+		{source: "rsi 14 > 70", wantErr: `invalid indicator "rsi 14", use a call such as ema(12)`},
+		{source: "1 > 2", wantErr: `indicator rule "1 > 2" compares two numbers`},
+		{source: "rsi(14)", wantErr: `invalid indicator rule "rsi(14)", use a condition such as rsi(14) > 70 or ema(12) crosses ema(26)`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			rule, err := NewIndicatorRule(tt.source)
+
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, rule.String())
+		})
+	}
+}
+
+func TestIndicatorRule(t *testing.T) {
+	tests := []struct {
+		source    string
+		prices    []float64
+		wantFired []bool
+	}{
+		{
+			source:    "price crosses sma(3)",
+			prices:    []float64{10, 10, 10, 9, 11, 12, 8},
+			wantFired: []bool{false, false, false, false, true, false, true},
+		},
+		{
+			source:    "price crosses above sma(3)",
+			prices:    []float64{10, 10, 10, 9, 11, 12, 8},
+			wantFired: []bool{false, false, false, false, true, false, false},
+		},
+		{
+			source:    "rsi(2) > 70",
+			prices:    []float64{10, 11, 12, 13, 8, 14},
+			wantFired: []bool{false, false, true, false, false, true},
+		},
+	}
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			rule, err := NewIndicatorRule(tt.source)
+			require.NoError(t, err)
+
+			ticker := NewTicker("BTCUSD", 10, 5, 0)
+			ticker.Config.Rules = []Rule{rule}
+
+			for i, price := range tt.prices {
+				at := start.Add(time.Duration(i) * time.Minute)
+
+				ticker.CurrentAsk = Float64(price)
+				ticker.RecordBar(at)
+
+				fired, err := rule.Evaluate(ticker, at)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantFired[i], fired, "price %d", i)
+			}
+		})
+	}
+
+	t.Run("Sets the indicator values", func(t *testing.T) {
+		rule, _ := NewIndicatorRule("price crosses sma(3)")
+
+		ticker := NewTicker("BTCUSD", 10, 5, 0)
+		ticker.Config.Rules = []Rule{rule}
+
+		for i, price := range []float64{10, 10, 9, 12} {
+			ticker.CurrentAsk = Float64(price)
+			ticker.RecordBar(start.Add(time.Duration(i) * time.Minute))
+			rule.Evaluate(ticker, start)
+		}
+
+		assert.Equal(t, IndicatorValues{"price": 12, "SMA(3)": 31.0 / 3}, ticker.Indicators)
+		assert.Equal(t, "SMA(3)=10.333333, price=12", ticker.Indicators.String())
+	})
+}
+
+func TestRecordBar(t *testing.T) {
+	rule, _ := NewIndicatorRule("rsi(14) > 70")
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	ticker := NewTicker("BTCUSD", 10, 5, 0)
+	ticker.Config.IndicatorInterval = time.Minute
+
+	ticker.CurrentAsk = 100
+	ticker.RecordBar(start)
+	assert.Empty(t, ticker.bars.closes(), "bars are only recorded for indicator rules")
+
+	ticker.Config.Rules = []Rule{rule}
+
+	for i, price := range []float64{100, 101, 102, 103} {
+		ticker.CurrentAsk = Float64(price)
+		ticker.RecordBar(start.Add(time.Duration(i) * 20 * time.Second))
+	}
+
+	assert.Equal(t, []float64{102, 103}, ticker.bars.closes(), "the newest price of an interval closes its bar")
+}
@@ -0,0 +1,52 @@
+package models
+
+// PriceLevel represents an absolute price target, alerted when the price crosses it on the level direction
+type PriceLevel struct {
+	Target    float64
+	Direction Direction
+}
+
+// sideOf returns on which side of the target the price is, 1 at or above it and -1 below it
+func (l PriceLevel) sideOf(price float64) int {
+	if price >= l.Target {
+		return 1
+	}
+
+	return -1
+}
+
+// CrossedLevels returns the levels the current price crossed since the previous call, each one with the direction it was crossed on.
+// A level fires once per cross, the first price received only sets on which side of each level the price is
+func (t *Ticker) CrossedLevels() []PriceLevel {
+	price := t.Price().Float64()
+	if price == 0 || len(t.Config.Levels) == 0 {
+		return nil
+	}
+
+	if len(t.levelSides) != len(t.Config.Levels) {
+		t.levelSides = make([]int, len(t.Config.Levels))
+	}
+
+	var crossed []PriceLevel
+
+	for i, level := range t.Config.Levels {
+		side := level.sideOf(price)
+		previous := t.levelSides[i]
+		t.levelSides[i] = side
+
+		if previous == 0 || previous == side {
+			continue
+		}
+
+		direction := DirectionUp
+		if side < 0 {
+			direction = DirectionDown
+		}
+
+		if level.Direction.Allows(direction) {
+			crossed = append(crossed, PriceLevel{Target: level.Target, Direction: direction})
+		}
+	}
+
+	return crossed
+}
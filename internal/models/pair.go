@@ -0,0 +1,27 @@
+package models
+
+import (
+	"github.com/pkg/errors"
+	"strings"
+)
+
+// DefaultExchange is the exchange used by tickers that don't specify one
+const DefaultExchange = "uphold"
+
+// ParsePair splits a pair written in the normalized BASE-QUOTE notation (also BASE/QUOTE or BASE_QUOTE) into its currencies
+func ParsePair(pair string) (string, string, error) {
+	parts := strings.FieldsFunc(strings.ToUpper(strings.TrimSpace(pair)), func(r rune) bool {
+		return r == '-' || r == '/' || r == '_'
+	})
+
+	if len(parts) != 2 {
+		return "", "", errors.Errorf("pair %s is not in the BASE-QUOTE notation (e.g. BTC-USD)", pair)
+	}
+
+	return parts[0], parts[1], nil
+}
+
+// CompactPair removes the separators from a pair, so BTC-USD and BTCUSD are compared as the same pair
+func CompactPair(pair string) string {
+	return strings.NewReplacer("-", "", "/", "", "_", "").Replace(strings.ToUpper(strings.TrimSpace(pair)))
+}
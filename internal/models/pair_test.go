@@ -0,0 +1,44 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePair(t *testing.T) {
+	tests := []struct {
+		pair      string
+		wantBase  string
+		wantQuote string
+		wantErr   bool
+	}{
+		{pair: "BTC-USD", wantBase: "BTC", wantQuote: "USD"},
+		{pair: "eth/eur", wantBase: "ETH", wantQuote: "EUR"},
+		{pair: " XRP_USDT ", wantBase: "XRP", wantQuote: "USDT"},
+		{pair: "BTCUSD", wantErr: true},
+		{pair: "BTC-USD-EUR", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.pair, func(t *testing.T) {
+			base, quote, err := ParsePair(tt.pair)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantBase, base)
+			assert.Equal(t, tt.wantQuote, quote)
+		})
+	}
+}
+
+func TestCompactPair(t *testing.T) {
+	assert.Equal(t, "BTCUSD", CompactPair("btc-usd"))
+	assert.Equal(t, "ETHEUR", CompactPair("ETH/EUR"))
+	assert.Equal(t, "XRPUSDT", CompactPair(" XRPUSDT "))
+}
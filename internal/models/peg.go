@@ -0,0 +1,106 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// PegStatus is the deviation of a pegged price from its target when a peg rule fires,
+// OutsideSeconds is how long the price has been, or was, outside the tolerance band
+type PegStatus struct {
+	Target         float64 `json:"target"`
+	Tolerance      float64 `json:"tolerance_pct"`
+	Deviation      float64 `json:"deviation_pct"`
+	OutsideSeconds float64 `json:"outside_seconds"`
+	Recovered      bool    `json:"recovered"`
+}
+
+// Outside returns how long the price has been, or was, outside the tolerance band
+func (s PegStatus) Outside() time.Duration {
+	return time.Duration(s.OutsideSeconds * float64(time.Second)).Round(time.Second)
+}
+
+// PegRule fires when the price deviates from the peg target by more than the tolerance percentage for the duration,
+// and fires again with a recovery once the price re-enters the tolerance band. It fires once per de-peg and once per recovery
+type PegRule struct {
+	Target    float64
+	Tolerance float64
+	For       time.Duration
+
+	outsideSince time.Time
+	depegged     bool
+	recovered    bool
+}
+
+// Type returns AlertDepeg, or AlertPegRecovered when the rule last fired on a recovery
+func (r *PegRule) Type() AlertType {
+	if r.recovered {
+		return AlertPegRecovered
+	}
+
+	return AlertDepeg
+}
+
+// Evaluate checks if the price left the tolerance band for the rule duration or re-entered it after a de-peg,
+// setting the deviation on the ticker Peg when it fires
+func (r *PegRule) Evaluate(ticker *Ticker, at time.Time) (bool, error) {
+	price := ticker.Price().Float64()
+	if price == 0 {
+		return false, nil
+	}
+
+	deviation := (price - r.Target) / r.Target * 100
+
+	if math.Abs(deviation) <= r.Tolerance {
+		outsideSince := r.outsideSince
+		r.outsideSince = time.Time{}
+
+		if !r.depegged {
+			return false, nil
+		}
+
+		r.depegged = false
+		r.recovered = true
+		r.setStatus(ticker, deviation, at.Sub(outsideSince))
+
+		return true, nil
+	}
+
+	if r.outsideSince.IsZero() {
+		r.outsideSince = at
+	}
+
+	if r.depegged || at.Sub(r.outsideSince) < r.For {
+		return false, nil
+	}
+
+	r.depegged = true
+	r.recovered = false
+	r.setStatus(ticker, deviation, at.Sub(r.outsideSince))
+
+	return true, nil
+}
+
+// setStatus sets the deviation from the peg on the ticker, along with its price and percentage changes from the target
+func (r *PegRule) setStatus(ticker *Ticker, deviation float64, outside time.Duration) {
+	ticker.Peg = &PegStatus{
+		Target:         r.Target,
+		Tolerance:      r.Tolerance,
+		Deviation:      deviation,
+		OutsideSeconds: outside.Seconds(),
+		Recovered:      r.recovered,
+	}
+
+	ticker.PriceChange = ticker.Price().Float64() - r.Target
+	ticker.PercChange = deviation
+}
+
+func (r *PegRule) String() string {
+	condition := fmt.Sprintf("peg %g ± %g%%", r.Target, r.Tolerance)
+	if r.For > 0 {
+		condition += " for " + r.For.String()
+	}
+
+	return condition
+}
@@ -0,0 +1,91 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPegRule(t *testing.T) {
+	type step struct {
+		offset    time.Duration
+		price     Float64
+		wantType  AlertType
+		wantFired bool
+	}
+
+	tests := []struct {
+		name  string
+		rule  *PegRule
+		steps []step
+	}{
+		{
+			name: "Fires once outside the band and once back within it",
+			rule: &PegRule{Target: 1, Tolerance: 0.5},
+			steps: []step{
+				{price: 1.002},
+				{offset: time.Minute, price: 0.99, wantType: AlertDepeg, wantFired: true},
+				{offset: 2 * time.Minute, price: 0.98},
+				{offset: 3 * time.Minute, price: 0.999, wantType: AlertPegRecovered, wantFired: true},
+				{offset: 4 * time.Minute, price: 1},
+			},
+		},
+		{
+			name: "Waits for the duration outside the band",
+			rule: &PegRule{Target: 1, Tolerance: 0.5, For: 5 * time.Minute},
+			steps: []step{
+				{price: 0.99},
+				{offset: 4 * time.Minute, price: 0.99},
+				{offset: 5 * time.Minute, price: 1.001},
+				{offset: 6 * time.Minute, price: 1.01},
+				{offset: 11 * time.Minute, price: 1.02, wantType: AlertDepeg, wantFired: true},
+				{offset: 12 * time.Minute, price: 1.02},
+			},
+		},
+	}
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ticker := NewTicker("USDTUSD", 10, 0, 0)
+
+			for i, step := range tt.steps {
+				ticker.CurrentAsk = step.price
+
+				fired, err := tt.rule.Evaluate(ticker, start.Add(step.offset))
+				assert.NoError(t, err)
+				assert.Equal(t, step.wantFired, fired, "step %d", i)
+
+				if fired {
+					assert.Equal(t, step.wantType, tt.rule.Type(), "step %d", i)
+				}
+			}
+		})
+	}
+}
+
+func TestPegStatus(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	rule := &PegRule{Target: 1, Tolerance: 0.5, For: time.Minute}
+	ticker := NewTicker("USDCUSD", 10, 0, 0)
+
+	ticker.CurrentAsk = 0.99
+	rule.Evaluate(ticker, start)
+	rule.Evaluate(ticker, start.Add(90*time.Second))
+
+	assert.Equal(t, &PegStatus{Target: 1, Tolerance: 0.5, Deviation: -1.0000000000000009, OutsideSeconds: 90}, ticker.Peg)
+	assert.InDelta(t, -0.01, ticker.PriceChange, 1e-9)
+	assert.Equal(t, "peg 1 ± 0.5% for 1m0s", rule.String())
+
+	ticker.AlertType = rule.Type()
+	assert.Equal(t, DirectionDown, ticker.ChangeDirection(), "de-pegs carry the side of the peg the price left on")
+
+	ticker.CurrentAsk = 1
+	rule.Evaluate(ticker, start.Add(10*time.Minute))
+
+	assert.True(t, ticker.Peg.Recovered)
+	assert.Equal(t, 10*time.Minute, ticker.Peg.Outside())
+}
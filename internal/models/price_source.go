@@ -0,0 +1,47 @@
+package models
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// PriceSource is the price of a ticker its changes are evaluated on
+type PriceSource string
+
+const (
+	// PriceAsk evaluates the ask price, from the buyer's perspective
+	PriceAsk PriceSource = "ask"
+	// PriceBid evaluates the bid price, from the seller's perspective
+	PriceBid PriceSource = "bid"
+	// PriceMid evaluates the middle between the ask and bid prices
+	PriceMid PriceSource = "mid"
+	// PriceLast evaluates the last trade price, only provided by some exchanges
+	PriceLast PriceSource = "last"
+)
+
+// ParsePriceSource parses a price source name, an empty one meaning the ask price
+func ParsePriceSource(s string) (PriceSource, error) {
+	switch source := PriceSource(strings.ToLower(strings.TrimSpace(s))); source {
+	case "":
+		return PriceAsk, nil
+	case PriceAsk, PriceBid, PriceMid, PriceLast:
+		return source, nil
+	default:
+		return "", errors.Errorf("unknown price source %q, use ask, bid, mid or last", s)
+	}
+}
+
+// price returns the price of the source out of the ask, bid and last prices
+func (s PriceSource) price(ask, bid, last Float64) Float64 {
+	switch s {
+	case PriceBid:
+		return bid
+	case PriceMid:
+		return (ask + bid) / 2
+	case PriceLast:
+		return last
+	default:
+		return ask
+	}
+}
@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// Quote represents the ask, bid and last trade prices of a pair at a given time
+type Quote struct {
+	Ask  Float64
+	Bid  Float64
+	Last Float64
+	Time time.Time
+}
+
+// ApplyQuote sets the quote prices as the ticker current prices
+func (t *Ticker) ApplyQuote(quote Quote) {
+	t.CurrentAsk = quote.Ask
+	t.CurrentBid = quote.Bid
+	t.CurrentLast = quote.Last
+}
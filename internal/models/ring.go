@@ -0,0 +1,60 @@
+package models
+
+// ring is a ring buffer growing by doubling until its limit, once reached the oldest items are overwritten
+type ring[T any] struct {
+	items []T
+	start int
+	size  int
+	limit int
+}
+
+// push appends the item as the newest one
+func (r *ring[T]) push(item T) {
+	if r.size == len(r.items) {
+		if len(r.items) < r.limit {
+			r.grow()
+		} else {
+			r.dropOldest()
+		}
+	}
+
+	r.items[(r.start+r.size)%len(r.items)] = item
+	r.size++
+}
+
+// grow doubles the buffer capacity up to the limit, keeping the items in order
+func (r *ring[T]) grow() {
+	items := make([]T, min(max(2*len(r.items), 16), r.limit))
+	for i := 0; i < r.size; i++ {
+		items[i] = r.at(i)
+	}
+
+	r.items = items
+	r.start = 0
+}
+
+// at returns the i-th oldest item
+func (r *ring[T]) at(i int) T {
+	return r.items[(r.start+i)%len(r.items)]
+}
+
+// dropOldest removes the oldest item
+func (r *ring[T]) dropOldest() {
+	r.start = (r.start + 1) % len(r.items)
+	r.size--
+}
+
+// keepNewest drops every item but the newest one
+func (r *ring[T]) keepNewest() {
+	if r.size == 0 {
+		return
+	}
+
+	r.start = (r.start + r.size - 1) % len(r.items)
+	r.size = 1
+}
+
+// newest returns the newest item, to be updated in place
+func (r *ring[T]) newest() *T {
+	return &r.items[(r.start+r.size-1)%len(r.items)]
+}
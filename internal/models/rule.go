@@ -0,0 +1,82 @@
+package models
+
+import (
+	"crypto-alert-bot/internal/expr"
+	"time"
+)
+
+// Rule is an alert condition evaluated on every price of a ticker
+type Rule interface {
+	// Type returns the type of the alerts fired by the rule
+	Type() AlertType
+	// Evaluate checks if the rule fires on the ticker price observed at the given time
+	Evaluate(ticker *Ticker, at time.Time) (bool, error)
+	// String returns the condition of the rule
+	String() string
+}
+
+// ThresholdRule is the builtin rule firing when the price change is above the ticker thresholds,
+// the baseline only moves once its alert is delivered, see NormalizeValues
+type ThresholdRule struct{}
+
+// Type returns AlertThreshold
+func (ThresholdRule) Type() AlertType {
+	return AlertThreshold
+}
+
+// Evaluate checks if the ticker is above the threshold of the change direction
+func (ThresholdRule) Evaluate(ticker *Ticker, at time.Time) (bool, error) {
+	return ticker.IsAboveThreshold(at), nil
+}
+
+func (ThresholdRule) String() string {
+	return string(AlertThreshold)
+}
+
+// ExpressionRule fires when its expression over the ticker quotes holds
+type ExpressionRule struct {
+	program *expr.Program
+}
+
+// NewExpressionRule compiles the expression into a rule, failing on syntax and type errors
+func NewExpressionRule(source string) (*ExpressionRule, error) {
+	names := make([]string, 0, len(series))
+	for name := range series {
+		names = append(names, name)
+	}
+
+	program, err := expr.Compile(source, names...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ExpressionRule{program: program}, nil
+}
+
+// Type returns AlertExpression
+func (r *ExpressionRule) Type() AlertType {
+	return AlertExpression
+}
+
+// Evaluate evaluates the expression on the quotes recorded by the ticker
+func (r *ExpressionRule) Evaluate(ticker *Ticker, at time.Time) (bool, error) {
+	return r.program.Eval(quoteEnv{ticker: ticker, at: at})
+}
+
+// Lookback returns the longest quote history read by the expression
+func (r *ExpressionRule) Lookback() time.Duration {
+	return r.program.Lookback()
+}
+
+func (r *ExpressionRule) String() string {
+	return r.program.String()
+}
+
+// AlertRules returns the rules evaluated on the ticker, the builtin threshold rule when none are configured
+func (c TickerConfig) AlertRules() []Rule {
+	if len(c.Rules) == 0 {
+		return []Rule{ThresholdRule{}}
+	}
+
+	return c.Rules
+}
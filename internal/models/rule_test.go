@@ -0,0 +1,68 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpressionRule(t *testing.T) {
+	rule, err := NewExpressionRule("pct_change(ask, 10m) > 3 && spread_pct < 0.5")
+	require.NoError(t, err)
+	assert.Equal(t, 10*time.Minute, rule.Lookback())
+
+	ticker := NewTicker("BTCUSD", 60, 5.0, 0)
+	ticker.Config.Rules = []Rule{ThresholdRule{}, rule}
+
+	steps := []struct {
+		after time.Duration
+		ask   Float64
+		bid   Float64
+		want  bool
+	}{
+		{after: 0, ask: 100, bid: 99},
+		{after: 5 * time.Minute, ask: 102, bid: 101.8},
+		{after: 9 * time.Minute, ask: 104, bid: 102},
+		{after: 10 * time.Minute, ask: 104, bid: 103.8, want: true},
+		{after: 16 * time.Minute, ask: 104.5, bid: 104.3},
+	}
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for _, step := range steps {
+		ticker.CurrentAsk = step.ask
+		ticker.CurrentBid = step.bid
+		ticker.RecordQuote(start.Add(step.after))
+
+		fired, err := rule.Evaluate(ticker, start.Add(step.after))
+		assert.NoError(t, err)
+		assert.Equal(t, step.want, fired, "ask %v after %v", step.ask, step.after)
+	}
+
+	assert.Equal(t, 3, ticker.quotes.size, "quotes older than the lookback are dropped")
+}
+
+func TestNewExpressionRule(t *testing.T) {
+	_, err := NewExpressionRule("pct_change(volume, 1h) > 3")
+	assert.EqualError(t, err, `column 12: unknown series "volume"`)
+
+	for _, source := range []string{"ask > bid", "last > 0", "mid > 0", "price > 0", "spread > 0", "spread_pct > 0"} {
+		_, err := NewExpressionRule(source)
+		assert.NoError(t, err, source)
+	}
+}
+
+func TestAlertRules(t *testing.T) {
+	config := TickerConfig{}
+	assert.Equal(t, []Rule{ThresholdRule{}}, config.AlertRules(), "the threshold rule is the default")
+	assert.Zero(t, config.lookback())
+
+	rule, err := NewExpressionRule("avg(price, 1h) < price")
+	require.NoError(t, err)
+
+	config.Rules = []Rule{rule}
+	assert.Equal(t, []Rule{rule}, config.AlertRules())
+	assert.Equal(t, time.Hour, config.lookback())
+}
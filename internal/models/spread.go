@@ -0,0 +1,99 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// spreadOf returns the absolute bid/ask spread of the quote and its percentage of the mid price
+func spreadOf(ask, bid Float64) (float64, float64) {
+	spread := ask.Float64() - bid.Float64()
+
+	mid := (ask.Float64() + bid.Float64()) / 2
+	if mid == 0 {
+		return spread, 0
+	}
+
+	return spread, spread / mid * 100
+}
+
+// Spread returns the current absolute bid/ask spread of the ticker
+func (t *Ticker) Spread() float64 {
+	spread, _ := spreadOf(t.CurrentAsk, t.CurrentBid)
+	return spread
+}
+
+// SpreadPct returns the current bid/ask spread of the ticker as a percentage of the mid price
+func (t *Ticker) SpreadPct() float64 {
+	_, pct := spreadOf(t.CurrentAsk, t.CurrentBid)
+	return pct
+}
+
+// SpreadRule fires when the bid/ask spread widens beyond its limits, or once it stayed wide for the duration when one is given.
+// It fires once per widening and re-arms when the spread narrows back within its limits, a limit is disabled when 0
+type SpreadRule struct {
+	MaxPct float64
+	Max    float64
+	For    time.Duration
+
+	wideSince time.Time
+	fired     bool
+}
+
+// Type returns AlertSpread
+func (r *SpreadRule) Type() AlertType {
+	return AlertSpread
+}
+
+// Evaluate checks if the spread of the ticker is wide, and has been for the rule duration
+func (r *SpreadRule) Evaluate(ticker *Ticker, at time.Time) (bool, error) {
+	if !r.isWide(ticker) {
+		r.wideSince = time.Time{}
+		r.fired = false
+
+		return false, nil
+	}
+
+	if r.wideSince.IsZero() {
+		r.wideSince = at
+	}
+
+	if r.fired || at.Sub(r.wideSince) < r.For {
+		return false, nil
+	}
+
+	r.fired = true
+
+	return true, nil
+}
+
+// isWide checks if the current spread of the ticker is beyond any of the limits
+func (r *SpreadRule) isWide(ticker *Ticker) bool {
+	if ticker.CurrentAsk == 0 || ticker.CurrentBid == 0 {
+		return false
+	}
+
+	spread, pct := spreadOf(ticker.CurrentAsk, ticker.CurrentBid)
+
+	return (r.MaxPct > 0 && pct > r.MaxPct) || (r.Max > 0 && spread > r.Max)
+}
+
+func (r *SpreadRule) String() string {
+	var limits []string
+
+	if r.MaxPct > 0 {
+		limits = append(limits, fmt.Sprintf("%g%%", r.MaxPct))
+	}
+
+	if r.Max > 0 {
+		limits = append(limits, fmt.Sprintf("%g", r.Max))
+	}
+
+	condition := "spread > " + strings.Join(limits, " or > ")
+	if r.For > 0 {
+		condition += " for " + r.For.String()
+	}
+
+	return condition
+}
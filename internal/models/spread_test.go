@@ -0,0 +1,91 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSpreadRule(t *testing.T) {
+	type step struct {
+		after time.Duration
+		bid   Float64
+		want  bool
+	}
+
+	tests := []struct {
+		name  string
+		rule  *SpreadRule
+		steps []step
+	}{
+		{
+			name: "Fires once per widening beyond the percentage",
+			rule: &SpreadRule{MaxPct: 0.5},
+			steps: []step{
+				{bid: 99.8},
+				{after: time.Minute, bid: 99, want: true},
+				{after: 2 * time.Minute, bid: 98},
+				{after: 3 * time.Minute, bid: 99.9},
+				{after: 4 * time.Minute, bid: 99, want: true},
+			},
+		},
+		{
+			name: "Fires beyond the absolute spread",
+			rule: &SpreadRule{Max: 1.5},
+			steps: []step{
+				{bid: 99},
+				{after: time.Minute, bid: 98, want: true},
+			},
+		},
+		{
+			name: "Fires once the spread stayed wide for the duration",
+			rule: &SpreadRule{MaxPct: 0.5, For: 5 * time.Minute},
+			steps: []step{
+				{bid: 99},
+				{after: 3 * time.Minute, bid: 99},
+				{after: 4 * time.Minute, bid: 99.9},
+				{after: 5 * time.Minute, bid: 99},
+				{after: 9 * time.Minute, bid: 99},
+				{after: 10 * time.Minute, bid: 98.5, want: true},
+				{after: 11 * time.Minute, bid: 98.5},
+			},
+		},
+		{
+			name: "Missing quotes are never wide",
+			rule: &SpreadRule{MaxPct: 0.5},
+			steps: []step{
+				{bid: 0},
+			},
+		},
+	}
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ticker := NewTicker("BTCUSD", 60, 5.0, 0)
+			ticker.CurrentAsk = 100
+
+			for _, step := range tt.steps {
+				ticker.CurrentBid = step.bid
+
+				fired, err := tt.rule.Evaluate(ticker, start.Add(step.after))
+				assert.NoError(t, err)
+				assert.Equal(t, step.want, fired, "bid %v after %v", step.bid, step.after)
+			}
+		})
+	}
+}
+
+func TestSpread(t *testing.T) {
+	ticker := NewTicker("BTCUSD", 60, 5.0, 0)
+	ticker.CurrentAsk = 101
+	ticker.CurrentBid = 99
+
+	assert.Equal(t, 2.0, ticker.Spread())
+	assert.Equal(t, 2.0, ticker.SpreadPct())
+
+	assert.Equal(t, "spread > 0.5% or > 20 for 5m0s", (&SpreadRule{MaxPct: 0.5, Max: 20, For: 5 * time.Minute}).String())
+	assert.Equal(t, "spread > 0.5%", (&SpreadRule{MaxPct: 0.5}).String())
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// StaleReason tells why the data of a ticker is considered stale
+type StaleReason string
+
+const (
+	// StaleFrozen is reported when the quotes keep being received unchanged
+	StaleFrozen StaleReason = "frozen"
+	// StaleNoData is reported when no quote is received
+	StaleNoData StaleReason = "no_data"
+)
+
+// StaleStatus describes the stale data of a ticker, Since is the time of the last quote received or of the last change
+type StaleStatus struct {
+	Reason StaleReason `json:"reason"`
+	Since  time.Time   `json:"since"`
+}
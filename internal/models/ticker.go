@@ -2,29 +2,81 @@ package models
 
 import (
 	"math"
+	"strings"
 	"time"
 )
 
-var rateLimit = 250
+// DefaultRateLimit is the calls per minute allowed to an exchange without a configured rate limit
+const DefaultRateLimit = 250
+
+// RateLimits holds the calls per minute allowed to each exchange
+type RateLimits map[string]int
+
+// Of returns the calls per minute allowed to the exchange, DefaultRateLimit when not configured
+func (l RateLimits) Of(exchange string) int {
+	if limit, ok := l[exchange]; ok {
+		return limit
+	}
+
+	return DefaultRateLimit
+}
 
 // Ticker represents a trading pair entity
 type Ticker struct {
-	Pair           string
-	Currency       string  `json:"currency"`
-	CurrentAsk     Float64 `json:"ask"`
-	CurrentBid     Float64 `json:"bid"`
-	PreviousAsk    Float64
-	PreviousBid    Float64
-	AskPriceChange float64
-	AskPercChange  float64
-	Config         TickerConfig
-}
-
-// TickerConfig represents the configuration settings for a ticker
+	Pair         string
+	Exchange     string
+	Currency     string  `json:"currency"`
+	CurrentAsk   Float64 `json:"ask"`
+	CurrentBid   Float64 `json:"bid"`
+	CurrentLast  Float64
+	PreviousAsk  Float64
+	PreviousBid  Float64
+	PreviousLast Float64
+	PriceChange  float64
+	PercChange   float64
+	AlertType    AlertType
+	Level        *PriceLevel
+	Rule         Rule
+	Divergence   *Divergence
+	Indicators   IndicatorValues
+	Peg          *PegStatus
+	Stale        *StaleStatus
+	Suppressed   int
+	Board        *QuoteBoard
+	Config       TickerConfig
+
+	levelSides []int
+	window     priceWindow
+	alertMarks map[string]alertMark
+	quotes     quoteHistory
+	bars       indicatorBars
+	returns    priceReturns
+	baseTicks  int
+	spanTicks  int
+}
+
+// TickerConfig represents the configuration settings for a ticker, the up and down thresholds default to PercOscillation when 0.
+// Cooldown, Rearm, a percentage, and StaleAfter are disabled when 0. A positive ZScore makes the thresholds adaptive, see Ticker.Threshold
 type TickerConfig struct {
-	RefreshRate     float64
-	PercOscillation float64
-	Lifetime        time.Duration
+	RefreshRate         float64
+	PercOscillation     float64
+	PercOscillationUp   float64
+	PercOscillationDown float64
+	Direction           Direction
+	PriceSource         PriceSource
+	Levels              []PriceLevel
+	Window              time.Duration
+	WindowBaseline      WindowBaseline
+	ZScore              float64
+	VolatilityWindow    time.Duration
+	Cooldown            time.Duration
+	Rearm               float64
+	StaleAfter          time.Duration
+	Rules               []Rule
+	IndicatorInterval   time.Duration
+	Lifetime            time.Duration
+	Stream              bool
+	Channels            []string
 }
 
 // NewTicker creates a new ticker entity
@@ -32,57 +84,225 @@ func NewTicker(pair string, refreshRate float64, percOscillation float64, lifeti
 	return &Ticker{
 		Pair: pair,
 		Config: TickerConfig{
-			RefreshRate:     refreshRate,
-			PercOscillation: percOscillation,
-			Lifetime:        lifetime,
+			RefreshRate:      refreshRate,
+			PercOscillation:  percOscillation,
+			Direction:        DirectionBoth,
+			PriceSource:      PriceAsk,
+			WindowBaseline:   BaselineExtreme,
+			VolatilityWindow: DefaultVolatilityWindow,
+			Lifetime:         lifetime,
 		},
 	}
 }
 
-// IsAbovePercOscillation checks if the current ask price is above the percentage oscillation threshold
+// IsAboveThreshold checks the price observed at the given time against the evaluation window when one is configured,
+// or against the previous price otherwise
+func (t *Ticker) IsAboveThreshold(at time.Time) bool {
+	if t.Config.Window > 0 {
+		return t.IsAboveWindowOscillation(at)
+	}
+
+	return t.IsAbovePercOscillation()
+}
+
+// IsAbovePercOscillation checks if the change from the previous price is above the percentage oscillation threshold of its direction.
+// The first price received only sets the baseline, which then stays in place until NormalizeValues moves it after an alert
 func (t *Ticker) IsAbovePercOscillation() bool {
-	t.setAskPriceChange()
-	t.setAskPercChange()
+	if t.PreviousPrice() == 0 {
+		t.NormalizeValues()
+		return false
+	}
+
+	t.baseTicks++
+	t.setChange(t.PreviousPrice().Float64(), t.baseTicks)
+
+	return t.isChangeAboveThreshold()
+}
 
-	if t.PreviousAsk != 0 && t.AskPercChange >= t.Config.PercOscillation {
-		return true
+// IsAboveWindowOscillation records the price observed at the given time and checks if its change within the window is above
+// the percentage oscillation threshold of its direction. Prices older than the window are dropped, and the current price is
+// compared against the oldest price left with BaselineOpen, or against the window minimum for rises and maximum for drops with
+// BaselineExtreme. The first price received only opens the window, and NormalizeValues restarts it from the alerted price
+func (t *Ticker) IsAboveWindowOscillation(at time.Time) bool {
+	price := t.Price().Float64()
+	if price == 0 {
+		return false
 	}
 
+	t.window.evict(at.Add(-t.Config.Window))
+	defer t.window.push(windowPoint{at: at, price: price})
+
+	if t.window.size == 0 {
+		return false
+	}
+
+	open, low, high := t.window.bounds()
+
+	baselines := []float64{open}
+	if t.Config.WindowBaseline != BaselineOpen {
+		baselines = []float64{low, high}
+		if high-price > price-low {
+			baselines = []float64{high, low}
+		}
+	}
+
+	for _, baseline := range baselines {
+		t.setChange(baseline, t.window.size)
+		if t.isChangeAboveThreshold() {
+			return true
+		}
+	}
+
+	t.setChange(baselines[0], t.window.size)
+
 	return false
 }
 
-// NormalizeValues resets the previous ask and bid prices to the current ask and bid prices for futures calculations
-func (t *Ticker) setAskPriceChange() {
-	previousAsk := t.PreviousAsk.Float64()
-	currentAsk := t.CurrentAsk.Float64()
+// Price returns the current price of the ticker price source
+func (t *Ticker) Price() Float64 {
+	return t.Config.PriceSource.price(t.CurrentAsk, t.CurrentBid, t.CurrentLast)
+}
+
+// PreviousPrice returns the previous price of the ticker price source
+func (t *Ticker) PreviousPrice() Float64 {
+	return t.Config.PriceSource.price(t.PreviousAsk, t.PreviousBid, t.PreviousLast)
+}
+
+// ChangeDirection returns the direction of the current price change, the direction a level was crossed on for level alerts,
+// or none for expression, spread, arbitrage, indicator and peg recovery alerts
+func (t *Ticker) ChangeDirection() Direction {
+	if t.AlertType == AlertLevel && t.Level != nil {
+		return t.Level.Direction
+	}
+
+	switch t.AlertType {
+	case AlertExpression, AlertSpread, AlertArbitrage, AlertIndicator, AlertPegRecovered:
+		return DirectionNone
+	}
+
+	return t.priceDirection()
+}
+
+// priceDirection returns the direction of the current price change, regardless of the alert the ticker last published
+func (t *Ticker) priceDirection() Direction {
+	if t.PriceChange < 0 {
+		return DirectionDown
+	}
+
+	return DirectionUp
+}
+
+// Threshold returns the percentage oscillation threshold of the direction
+func (c TickerConfig) Threshold(direction Direction) float64 {
+	if direction == DirectionUp && c.PercOscillationUp > 0 {
+		return c.PercOscillationUp
+	}
+
+	if direction == DirectionDown && c.PercOscillationDown > 0 {
+		return c.PercOscillationDown
+	}
+
+	return c.PercOscillation
+}
 
-	t.AskPriceChange = math.Abs(previousAsk - currentAsk)
+// setChange calculates the signed price and percentage changes between the baseline and the current price,
+// observed the given number of ticks after the baseline
+func (t *Ticker) setChange(baseline float64, ticks int) {
+	t.PriceChange = t.Price().Float64() - baseline
+	t.PercChange = t.PriceChange / baseline * 100
+	t.spanTicks = ticks
 }
 
-// setAskPercChange calculates the percentage change between the previous ask price and the current ask price
-func (t *Ticker) setAskPercChange() {
-	t.AskPercChange = t.AskPriceChange / t.PreviousAsk.Float64() * 100
+// isChangeAboveThreshold checks if the current change is alerted on and above the threshold of its direction.
+// The direction comes from the price change alone, AlertType still holds the type of the previous alert at this point
+func (t *Ticker) isChangeAboveThreshold() bool {
+	direction := t.priceDirection()
+	if !t.Config.Direction.Allows(direction) {
+		return false
+	}
+
+	return math.Abs(t.PercChange) >= t.Threshold(direction)
 }
 
-// NormalizeValues resets the previous ask and bid prices to the current ask and bid prices for futures calculations
+// NormalizeValues resets the previous prices to the current prices for futures calculations,
+// and restarts the evaluation window from the current price
 func (t *Ticker) NormalizeValues() {
 	t.PreviousAsk = t.CurrentAsk
 	t.PreviousBid = t.CurrentBid
+	t.PreviousLast = t.CurrentLast
+	t.baseTicks = 0
+
+	t.window.restart()
+}
+
+// QuoteCurrency returns the currency the pair is quoted in, known after the first fetch or from the BASE-QUOTE notation
+func (t *Ticker) QuoteCurrency() string {
+	if t.Currency != "" {
+		return strings.ToUpper(t.Currency)
+	}
+
+	_, quote, err := ParsePair(t.Pair)
+	if err != nil {
+		return ""
+	}
+
+	return quote
+}
+
+// ExchangeName returns the ticker exchange, resolving an empty one to the default exchange
+func (t *Ticker) ExchangeName() string {
+	if t.Exchange == "" {
+		return DefaultExchange
+	}
+
+	return t.Exchange
+}
+
+// IsBatchable checks if the ticker can share a single currency request with other tickers, only supported by the default exchange
+func (t *Ticker) IsBatchable() bool {
+	return t.ExchangeName() == DefaultExchange && t.QuoteCurrency() != ""
 }
 
 type Tickers []*Ticker
 
-func (ts *Tickers) IsAboveRateLimit() bool {
-	totalCalls := 0
+// requestGroup identifies the tickers served by the same API request
+type requestGroup struct {
+	exchange    string
+	refreshRate float64
+	key         string
+}
+
+// IsAboveRateLimit checks if the calls per minute needed by the tickers exceed the rate limit of any exchange.
+// Batchable tickers with the same refresh rate and quote currency are served by a single request, so they count once,
+// and streamed tickers don't poll at all. Compact pairs (e.g. BTCUSD) are only batchable once their Currency is known
+func (ts *Tickers) IsAboveRateLimit(limits RateLimits) bool {
+	groups := make(map[requestGroup]bool)
+	callsPerExchange := make(map[string]int)
 
 	for _, ticker := range *ts {
-		call := int(math.Ceil(60 / ticker.Config.RefreshRate))
+		if ticker.Config.Stream {
+			continue
+		}
+
+		exchange := ticker.ExchangeName()
+
+		group := requestGroup{exchange: exchange, refreshRate: ticker.Config.RefreshRate, key: "pair:" + CompactPair(ticker.Pair)}
+		if ticker.IsBatchable() {
+			group.key = "currency:" + ticker.QuoteCurrency()
+		}
+
+		if groups[group] {
+			continue
+		}
+		groups[group] = true
 
-		totalCalls = totalCalls + call
+		callsPerExchange[exchange] += int(math.Ceil(60 / ticker.Config.RefreshRate))
 	}
 
-	if totalCalls > rateLimit {
-		return true
+	for exchange, calls := range callsPerExchange {
+		if calls > limits.Of(exchange) {
+			return true
+		}
 	}
 
 	return false
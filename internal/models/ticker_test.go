@@ -27,6 +27,9 @@ func TestIsAbovePercOscillation(t *testing.T) {
 		previousAsk     Float64
 		currentAsk      Float64
 		percOscillation float64
+		up              float64
+		down            float64
+		direction       Direction
 		wantIsAbove     bool
 	}{
 		{
@@ -57,6 +60,45 @@ func TestIsAbovePercOscillation(t *testing.T) {
 			percOscillation: 5.0,
 			wantIsAbove:     true,
 		},
+		{
+			name:            "Ask drop above threshold",
+			previousAsk:     100.0,
+			currentAsk:      90.0,
+			percOscillation: 5.0,
+			wantIsAbove:     true,
+		},
+		{
+			name:            "Ask drop ignored on rise only",
+			previousAsk:     100.0,
+			currentAsk:      90.0,
+			percOscillation: 5.0,
+			direction:       DirectionUp,
+			wantIsAbove:     false,
+		},
+		{
+			name:            "Ask rise ignored on drop only",
+			previousAsk:     100.0,
+			currentAsk:      110.0,
+			percOscillation: 5.0,
+			direction:       DirectionDown,
+			wantIsAbove:     false,
+		},
+		{
+			name:            "Ask rise below up threshold",
+			previousAsk:     100.0,
+			currentAsk:      106.0,
+			percOscillation: 5.0,
+			up:              10.0,
+			wantIsAbove:     false,
+		},
+		{
+			name:            "Ask drop above down threshold",
+			previousAsk:     100.0,
+			currentAsk:      98.0,
+			percOscillation: 5.0,
+			down:            2.0,
+			wantIsAbove:     true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -66,7 +108,10 @@ func TestIsAbovePercOscillation(t *testing.T) {
 				PreviousAsk: tt.previousAsk,
 				CurrentAsk:  tt.currentAsk,
 				Config: TickerConfig{
-					PercOscillation: tt.percOscillation,
+					PercOscillation:     tt.percOscillation,
+					PercOscillationUp:   tt.up,
+					PercOscillationDown: tt.down,
+					Direction:           tt.direction,
 				},
 			}
 
@@ -76,6 +121,104 @@ func TestIsAbovePercOscillation(t *testing.T) {
 	}
 }
 
+func TestIsAbovePercOscillationBaseline(t *testing.T) {
+	ticker := NewTicker("BTCUSD", 1, 5.0, 0)
+
+	ticker.CurrentAsk = 100.0
+	assert.False(t, ticker.IsAbovePercOscillation())
+	assert.Equal(t, 100.0, ticker.PreviousAsk.Float64(), "first price sets the baseline")
+
+	ticker.CurrentAsk = 106.0
+	assert.True(t, ticker.IsAbovePercOscillation())
+}
+
+func TestSignedChange(t *testing.T) {
+	ticker := NewTicker("BTCUSD", 1, 5.0, 0)
+	ticker.PreviousAsk = 200.0
+	ticker.CurrentAsk = 190.0
+
+	assert.True(t, ticker.IsAbovePercOscillation())
+	assert.Equal(t, -10.0, ticker.PriceChange)
+	assert.Equal(t, -5.0, ticker.PercChange)
+	assert.Equal(t, DirectionDown, ticker.ChangeDirection())
+}
+
+func TestPriceSource(t *testing.T) {
+	tests := []struct {
+		source      PriceSource
+		wantPrice   float64
+		wantPerc    float64
+		wantIsAbove bool
+	}{
+		{source: PriceAsk, wantPrice: 104, wantPerc: 4, wantIsAbove: true},
+		{source: PriceBid, wantPrice: 98, wantPerc: -2, wantIsAbove: false},
+		{source: PriceMid, wantPrice: 101, wantPerc: 1, wantIsAbove: false},
+		{source: PriceLast, wantPrice: 95, wantPerc: -5, wantIsAbove: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.source), func(t *testing.T) {
+			ticker := NewTicker("BTCUSD", 1, 3.0, 0)
+			ticker.Config.PriceSource = tt.source
+			ticker.PreviousAsk, ticker.PreviousBid, ticker.PreviousLast = 100, 100, 100
+			ticker.CurrentAsk, ticker.CurrentBid, ticker.CurrentLast = 104, 98, 95
+
+			assert.Equal(t, tt.wantIsAbove, ticker.IsAbovePercOscillation())
+			assert.Equal(t, tt.wantPrice, ticker.Price().Float64())
+			assert.InDelta(t, tt.wantPerc, ticker.PercChange, 1e-9)
+		})
+	}
+}
+
+func TestCrossedLevels(t *testing.T) {
+	ticker := NewTicker("BTCUSD", 1, 5.0, 0)
+	ticker.Config.Levels = []PriceLevel{
+		{Target: 70000, Direction: DirectionUp},
+		{Target: 60000, Direction: DirectionDown},
+		{Target: 65000, Direction: DirectionBoth},
+	}
+
+	steps := []struct {
+		ask  Float64
+		want []PriceLevel
+	}{
+		{ask: 66000, want: nil},
+		{ask: 71000, want: []PriceLevel{{Target: 70000, Direction: DirectionUp}}},
+		{ask: 72000, want: nil},
+		{ask: 64000, want: []PriceLevel{{Target: 65000, Direction: DirectionDown}}},
+		{ask: 59000, want: []PriceLevel{{Target: 60000, Direction: DirectionDown}}},
+		{ask: 70000, want: []PriceLevel{{Target: 70000, Direction: DirectionUp}, {Target: 65000, Direction: DirectionUp}}},
+	}
+
+	for _, step := range steps {
+		ticker.CurrentAsk = step.ask
+		assert.Equal(t, step.want, ticker.CrossedLevels(), "ask %v", step.ask)
+	}
+}
+
+func TestParseDirection(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Direction
+		wantErr bool
+	}{
+		{input: "", want: DirectionBoth},
+		{input: "Up", want: DirectionUp},
+		{input: "down", want: DirectionDown},
+		{input: "both", want: DirectionBoth},
+		{input: "sideways", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseDirection(tt.input)
+
+			assert.Equal(t, tt.wantErr, err != nil)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
 func TestNormalizeValues(t *testing.T) {
 	ticker := &Ticker{
 		CurrentAsk:  120.0,
@@ -91,3 +234,88 @@ func TestNormalizeValues(t *testing.T) {
 	assert.Equal(t, float64(ticker.CurrentBid), float64(ticker.PreviousBid),
 		"PreviousBid should be updated to CurrentBid.")
 }
+
+func TestIsAboveRateLimit(t *testing.T) {
+	tests := []struct {
+		name    string
+		tickers Tickers
+		limits  RateLimits
+		want    bool
+	}{
+		{
+			name: "Below rate limit",
+			tickers: Tickers{
+				NewTicker("BTCUSD", 1, 1, 0),
+				NewTicker("ETHUSD", 1, 1, 0),
+			},
+			want: false,
+		},
+		{
+			name: "Above rate limit with single pair requests",
+			tickers: Tickers{
+				NewTicker("BTCUSD", 0.5, 1, 0),
+				NewTicker("ETHUSD", 0.5, 1, 0),
+				NewTicker("XRPUSD", 0.5, 1, 0),
+			},
+			want: true,
+		},
+		{
+			name: "Batched pairs with the same quote currency and refresh rate count once",
+			tickers: Tickers{
+				NewTicker("BTC-USD", 0.5, 1, 0),
+				NewTicker("ETH-USD", 0.5, 1, 0),
+				NewTicker("XRP-USD", 0.5, 1, 0),
+			},
+			want: false,
+		},
+		{
+			name: "Rate limit is counted per exchange",
+			tickers: Tickers{
+				NewTicker("BTCUSD", 0.5, 1, 0),
+				NewTicker("ETHUSD", 0.5, 1, 0),
+				{Pair: "XRP-USD", Exchange: "kraken", Config: TickerConfig{RefreshRate: 0.5}},
+			},
+			want: false,
+		},
+		{
+			name: "Streamed tickers don't count",
+			tickers: Tickers{
+				NewTicker("BTCUSD", 0.5, 1, 0),
+				NewTicker("ETHUSD", 0.5, 1, 0),
+				{Pair: "XRPUSD", Config: TickerConfig{RefreshRate: 0.5, Stream: true}},
+			},
+			want: false,
+		},
+		{
+			name: "Above a configured rate limit",
+			tickers: Tickers{
+				NewTicker("BTCUSD", 1, 1, 0),
+				NewTicker("ETHUSD", 1, 1, 0),
+			},
+			limits: RateLimits{DefaultExchange: 60},
+			want:   true,
+		},
+		{
+			name: "Configured rate limit only applies to its exchange",
+			tickers: Tickers{
+				{Pair: "BTC-USD", Exchange: "kraken", Config: TickerConfig{RefreshRate: 0.5}},
+				{Pair: "ETH-USD", Exchange: "kraken", Config: TickerConfig{RefreshRate: 0.5}},
+			},
+			limits: RateLimits{DefaultExchange: 60},
+			want:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.tickers.IsAboveRateLimit(tt.limits))
+		})
+	}
+}
+
+func TestQuoteCurrency(t *testing.T) {
+	assert.Equal(t, "USD", (&Ticker{Pair: "BTCUSD", Currency: "usd"}).QuoteCurrency())
+	assert.Equal(t, "EUR", (&Ticker{Pair: "ETH-EUR"}).QuoteCurrency())
+	assert.Equal(t, "", (&Ticker{Pair: "ETHEUR"}).QuoteCurrency())
+}
@@ -0,0 +1,92 @@
+package models
+
+import (
+	"math"
+	"time"
+)
+
+// maxVolatilityReturns bounds the returns kept per ticker, once reached the oldest returns are overwritten
+const maxVolatilityReturns = 4096
+
+// MinVolatilityReturns is the number of returns needed before the realized volatility replaces the configured threshold
+const MinVolatilityReturns = 20
+
+// DefaultVolatilityWindow is the duration the realized volatility is measured over by default
+const DefaultVolatilityWindow = time.Hour
+
+// returnPoint is the percentage return between two consecutive prices, observed at a given time
+type returnPoint struct {
+	at  time.Time
+	pct float64
+}
+
+// priceReturns holds the returns between the consecutive prices observed within the volatility window, oldest first
+type priceReturns struct {
+	ring[returnPoint]
+	last float64
+}
+
+// deviation returns the standard deviation of the returns, false while fewer than MinVolatilityReturns were recorded
+func (r *priceReturns) deviation() (float64, bool) {
+	if r.size < MinVolatilityReturns {
+		return 0, false
+	}
+
+	values := make([]float64, r.size)
+	for i := range values {
+		values[i] = r.at(i).pct
+	}
+
+	return stddev(values), true
+}
+
+// RecordReturn records the return of the current price observed at the given time for adaptive thresholds,
+// dropping the returns older than the volatility window
+func (t *Ticker) RecordReturn(at time.Time) {
+	price := t.Price().Float64()
+	if price == 0 || t.Config.ZScore <= 0 {
+		return
+	}
+
+	if last := t.returns.last; last != 0 {
+		t.returns.limit = maxVolatilityReturns
+		t.returns.push(returnPoint{at: at, pct: (price - last) / last * 100})
+	}
+
+	t.returns.last = price
+
+	for t.returns.size > 0 && t.returns.at(0).at.Before(at.Add(-t.Config.VolatilityWindow)) {
+		t.returns.dropOldest()
+	}
+}
+
+// Volatility returns the realized volatility of the ticker, the standard deviation of the percentage returns between
+// consecutive prices within the volatility window, false while too few were recorded
+func (t *Ticker) Volatility() (float64, bool) {
+	return t.returns.deviation()
+}
+
+// Threshold returns the percentage threshold of the direction. With adaptive thresholds it's ZScore standard deviations
+// of the recent returns, scaled by the square root of the ticks the change spans since a random walk drifts that much
+// from its baseline, falling back to the configured threshold while too few returns were recorded, or never alerting
+// without one. Prices that didn't move over the whole volatility window never alert either
+func (t *Ticker) Threshold(direction Direction) float64 {
+	if t.Config.ZScore <= 0 {
+		return t.Config.Threshold(direction)
+	}
+
+	volatility, ok := t.Volatility()
+	if !ok {
+		if threshold := t.Config.Threshold(direction); threshold > 0 {
+			return threshold
+		}
+
+		return math.Inf(1)
+	}
+
+	if volatility == 0 {
+		return math.Inf(1)
+	}
+
+	return t.Config.ZScore * volatility * math.Sqrt(float64(max(t.spanTicks, 1)))
+}
@@ -0,0 +1,136 @@
+package models
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// recordPrices records the prices one minute apart, returning the time of the last one
+func recordPrices(ticker *Ticker, start time.Time, prices ...float64) time.Time {
+	at := start
+
+	for i, price := range prices {
+		at = start.Add(time.Duration(i) * time.Minute)
+
+		ticker.CurrentAsk = Float64(price)
+		ticker.RecordReturn(at)
+	}
+
+	return at
+}
+
+// alternate returns count prices alternating between low and high
+func alternate(low, high float64, count int) []float64 {
+	prices := make([]float64, count)
+	for i := range prices {
+		prices[i] = low
+		if i%2 == 1 {
+			prices[i] = high
+		}
+	}
+
+	return prices
+}
+
+func TestTickerThreshold(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		zscore    float64
+		threshold float64
+		prices    []float64
+		want      float64
+	}{
+		{name: "Fixed threshold", threshold: 2, prices: alternate(100, 101, 30), want: 2},
+		{name: "Z-score of the recent returns", zscore: 3, threshold: 2, prices: alternate(100, 101, 21), want: 2.985148514851485},
+		{name: "Fixed threshold while warming up", zscore: 3, threshold: 2, prices: alternate(100, 101, 20), want: 2},
+		{name: "Never alerts while warming up without a fixed threshold", zscore: 3, prices: alternate(100, 101, 20), want: math.Inf(1)},
+		{name: "Never alerts on flat prices", zscore: 3, threshold: 2, prices: alternate(100, 100, 30), want: math.Inf(1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ticker := NewTicker("BTCUSD", 60, tt.threshold, 0)
+			ticker.Config.ZScore = tt.zscore
+
+			recordPrices(ticker, start, tt.prices...)
+
+			if math.IsInf(tt.want, 1) {
+				assert.True(t, math.IsInf(ticker.Threshold(DirectionUp), 1))
+				return
+			}
+
+			assert.InDelta(t, tt.want, ticker.Threshold(DirectionUp), 1e-9)
+		})
+	}
+}
+
+func TestAdaptiveThreshold(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	stable := NewTicker("USDCUSD", 60, 0, 0)
+	stable.Config.ZScore = 3
+	recordPrices(stable, start, alternate(1, 1.0001, 30)...)
+
+	volatile := NewTicker("PEPEUSD", 60, 0, 0)
+	volatile.Config.ZScore = 3
+	last := recordPrices(volatile, start, alternate(1, 1.05, 30)...)
+
+	for _, ticker := range []*Ticker{stable, volatile} {
+		ticker.PreviousAsk = 1
+		ticker.CurrentAsk = 1.01
+	}
+
+	assert.True(t, stable.IsAboveThreshold(last), "a 1% move is extreme for a stable pair")
+	assert.False(t, volatile.IsAboveThreshold(last), "a 1% move is noise for a volatile pair")
+
+	volatile.CurrentAsk = 1.25
+	assert.True(t, volatile.IsAboveThreshold(last))
+
+	last = recordPrices(volatile, last.Add(time.Hour), alternate(1, 1.0001, 90)...)
+
+	volatile.CurrentAsk = 1.01
+	assert.True(t, volatile.IsAboveThreshold(last), "returns older than the volatility window are dropped")
+}
+
+func TestAdaptiveThresholdRandomWalk(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		window time.Duration
+	}{
+		{name: "Previous price baseline"},
+		{name: "Window baseline", window: 30 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ticker := NewTicker("BTCUSD", 60, 0, 0)
+			ticker.Config.ZScore = 4
+			ticker.Config.Window = tt.window
+
+			random := rand.New(rand.NewSource(1))
+			price := 100.0
+			at := start
+
+			for i := 0; i < 1000; i++ {
+				at = start.Add(time.Duration(i) * time.Minute)
+				price *= 1 + random.NormFloat64()/1000
+				ticker.CurrentAsk = Float64(price)
+
+				assert.False(t, ticker.IsAboveThreshold(at), "a random walk without trend alerted after %d ticks", i)
+
+				ticker.RecordReturn(at)
+			}
+
+			ticker.CurrentAsk = Float64(price * 1.25)
+			assert.True(t, ticker.IsAboveThreshold(at.Add(time.Minute)), "a 25%% jump alerts")
+		})
+	}
+}
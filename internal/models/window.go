@@ -0,0 +1,76 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// maxWindowPoints bounds the prices kept per window, once reached the oldest prices are overwritten
+const maxWindowPoints = 4096
+
+// WindowBaseline represents the price of the window the current price is compared against
+type WindowBaseline string
+
+const (
+	// BaselineExtreme compares rises against the window minimum and drops against the window maximum
+	BaselineExtreme WindowBaseline = "extreme"
+	// BaselineOpen compares every change against the oldest price of the window
+	BaselineOpen WindowBaseline = "open"
+)
+
+// ParseWindowBaseline parses a window baseline, an empty value is the extreme baseline
+func ParseWindowBaseline(value string) (WindowBaseline, error) {
+	switch baseline := WindowBaseline(strings.ToLower(strings.TrimSpace(value))); baseline {
+	case "":
+		return BaselineExtreme, nil
+	case BaselineExtreme, BaselineOpen:
+		return baseline, nil
+	default:
+		return "", errors.Errorf("unknown window baseline %q, use extreme or open", value)
+	}
+}
+
+// windowPoint is a price observed at a given time
+type windowPoint struct {
+	at    time.Time
+	price float64
+}
+
+// priceWindow holds the prices observed within the evaluation window, oldest first
+type priceWindow struct {
+	ring[windowPoint]
+}
+
+// push appends the point, overwriting the oldest one once maxWindowPoints are held
+func (w *priceWindow) push(point windowPoint) {
+	w.limit = maxWindowPoints
+	w.ring.push(point)
+}
+
+// evict drops the points observed before the given time
+func (w *priceWindow) evict(before time.Time) {
+	for w.size > 0 && w.at(0).at.Before(before) {
+		w.dropOldest()
+	}
+}
+
+// restart drops every point but the newest one
+func (w *priceWindow) restart() {
+	w.keepNewest()
+}
+
+// bounds returns the open, minimum and maximum prices of the window
+func (w *priceWindow) bounds() (open, low, high float64) {
+	open = w.at(0).price
+	low, high = open, open
+
+	for i := 1; i < w.size; i++ {
+		price := w.at(i).price
+		low = min(low, price)
+		high = max(high, price)
+	}
+
+	return open, low, high
+}
@@ -0,0 +1,146 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsAboveWindowOscillation(t *testing.T) {
+	type step struct {
+		after     time.Duration
+		ask       Float64
+		wantAbove bool
+		wantPerc  float64
+	}
+
+	tests := []struct {
+		name     string
+		window   time.Duration
+		baseline WindowBaseline
+		steps    []step
+	}{
+		{
+			name:   "Small consecutive rises add up within the window",
+			window: 15 * time.Minute,
+			steps: []step{
+				{after: 0, ask: 100},
+				{after: 5 * time.Minute, ask: 102, wantPerc: 2},
+				{after: 10 * time.Minute, ask: 104, wantPerc: 4},
+				{after: 14 * time.Minute, ask: 105.5, wantAbove: true, wantPerc: 5.5},
+			},
+		},
+		{
+			name:   "Prices older than the window are dropped",
+			window: 10 * time.Minute,
+			steps: []step{
+				{after: 0, ask: 100},
+				{after: 6 * time.Minute, ask: 103, wantPerc: 3},
+				{after: 12 * time.Minute, ask: 106, wantPerc: 2.912621359223301},
+			},
+		},
+		{
+			name:   "Extreme baseline compares rises with the window minimum",
+			window: 15 * time.Minute,
+			steps: []step{
+				{after: 0, ask: 100},
+				{after: 5 * time.Minute, ask: 90, wantAbove: true, wantPerc: -10},
+				{after: 10 * time.Minute, ask: 96, wantPerc: 6.666666666666667, wantAbove: true},
+			},
+		},
+		{
+			name:     "Open baseline compares every change with the oldest price",
+			window:   15 * time.Minute,
+			baseline: BaselineOpen,
+			steps: []step{
+				{after: 0, ask: 100},
+				{after: 5 * time.Minute, ask: 97, wantPerc: -3},
+				{after: 10 * time.Minute, ask: 96, wantPerc: -4},
+				{after: 11 * time.Minute, ask: 101, wantPerc: 1},
+			},
+		},
+	}
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ticker := NewTicker("BTCUSD", 60, 5.0, 0)
+			ticker.Config.Window = tt.window
+			if tt.baseline != "" {
+				ticker.Config.WindowBaseline = tt.baseline
+			}
+
+			for _, step := range tt.steps {
+				ticker.CurrentAsk = step.ask
+
+				isAbove := ticker.IsAboveThreshold(start.Add(step.after))
+				assert.Equal(t, step.wantAbove, isAbove, "ask %v", step.ask)
+				assert.InDelta(t, step.wantPerc, ticker.PercChange, 1e-9, "ask %v", step.ask)
+
+				if isAbove {
+					ticker.NormalizeValues()
+				}
+			}
+		})
+	}
+}
+
+func TestWindowRestartsAfterAlert(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	ticker := NewTicker("BTCUSD", 60, 5.0, 0)
+	ticker.Config.Window = 15 * time.Minute
+
+	for i, ask := range []Float64{100, 103, 105.5} {
+		ticker.CurrentAsk = ask
+		assert.Equal(t, i == 2, ticker.IsAboveThreshold(start.Add(time.Duration(i)*time.Minute)))
+	}
+
+	ticker.NormalizeValues()
+
+	ticker.CurrentAsk = 101
+	assert.False(t, ticker.IsAboveThreshold(start.Add(3*time.Minute)), "the window restarts from the alerted price")
+	assert.InDelta(t, -4.265402843601896, ticker.PercChange, 1e-9)
+
+	ticker.CurrentAsk = 100
+	assert.True(t, ticker.IsAboveThreshold(start.Add(4*time.Minute)))
+}
+
+func TestPriceWindow(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	var window priceWindow
+	for i := 0; i < 20; i++ {
+		window.push(windowPoint{at: start.Add(time.Duration(i) * time.Second), price: float64(i)})
+	}
+
+	window.evict(start.Add(5 * time.Second))
+	assert.Equal(t, 15, window.size)
+
+	open, low, high := window.bounds()
+	assert.Equal(t, []float64{5, 5, 19}, []float64{open, low, high})
+
+	window.restart()
+	assert.Equal(t, 1, window.size)
+	assert.Equal(t, 19.0, window.at(0).price, "restart keeps the newest price")
+
+	for i := 0; i < maxWindowPoints+10; i++ {
+		window.push(windowPoint{at: start, price: float64(i)})
+	}
+
+	assert.Equal(t, maxWindowPoints, window.size)
+	assert.Equal(t, 10.0, window.at(0).price, "the oldest prices are overwritten once full")
+}
+
+func TestParseWindowBaseline(t *testing.T) {
+	for value, want := range map[string]WindowBaseline{"": BaselineExtreme, "extreme": BaselineExtreme, "Open": BaselineOpen} {
+		baseline, err := ParseWindowBaseline(value)
+		assert.NoError(t, err)
+		assert.Equal(t, want, baseline)
+	}
+
+	_, err := ParseWindowBaseline("close")
+	assert.EqualError(t, err, `unknown window baseline "close", use extreme or open`)
+}
@@ -0,0 +1,174 @@
+package services
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// BreakerState represents the state of a CircuitBreaker
+type BreakerState int
+
+const (
+	// BreakerClosed lets every fetch through
+	BreakerClosed BreakerState = iota
+	// BreakerOpen pauses every fetch until the cooldown is over
+	BreakerOpen
+	// BreakerHalfOpen lets a single probe fetch through to check if the data source recovered
+	BreakerHalfOpen
+)
+
+// CircuitBreaker pauses the polling of a data source after repeated failures, it's shared by the schedulers of the same exchange
+type CircuitBreaker struct {
+	mu               sync.Mutex
+	name             string
+	failureThreshold int
+	cooldown         time.Duration
+	failures         int
+	state            BreakerState
+	openedAt         time.Time
+	probing          bool
+}
+
+// NewCircuitBreaker returns a new instance of CircuitBreaker that opens after failureThreshold consecutive failures
+func NewCircuitBreaker(name string, failureThreshold int, cooldown time.Duration) *CircuitBreaker {
+	if failureThreshold < 1 {
+		failureThreshold = 1
+	}
+
+	return &CircuitBreaker{
+		name:             name,
+		failureThreshold: failureThreshold,
+		cooldown:         cooldown,
+	}
+}
+
+// Name returns the name of the data source protected by the breaker
+func (b *CircuitBreaker) Name() string {
+	return b.name
+}
+
+// State returns the current state of the breaker
+func (b *CircuitBreaker) State() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.state
+}
+
+// Allow checks if a fetch can be done, once the cooldown is over a single probe is allowed
+func (b *CircuitBreaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case BreakerOpen:
+		if time.Since(b.openedAt) < b.cooldown {
+			return false
+		}
+
+		b.state = BreakerHalfOpen
+		b.probing = true
+
+		return true
+	case BreakerHalfOpen:
+		if b.probing {
+			return false
+		}
+
+		b.probing = true
+
+		return true
+	default:
+		return true
+	}
+}
+
+// Success records a successful fetch and returns true if it closed the breaker
+func (b *CircuitBreaker) Success() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures = 0
+	b.probing = false
+
+	if b.state == BreakerClosed {
+		return false
+	}
+
+	b.state = BreakerClosed
+
+	return true
+}
+
+// Failure records a failed fetch and returns true if it opened the breaker
+func (b *CircuitBreaker) Failure() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+
+	if b.state == BreakerHalfOpen {
+		b.state = BreakerOpen
+		b.openedAt = time.Now()
+
+		return false
+	}
+
+	if b.state == BreakerOpen {
+		return false
+	}
+
+	b.failures++
+
+	if b.failures < b.failureThreshold {
+		return false
+	}
+
+	b.state = BreakerOpen
+	b.openedAt = time.Now()
+
+	return true
+}
+
+// Release records a fetch that failed without telling anything about the exchange health, such as an unknown pair,
+// letting another probe through without changing the breaker state
+func (b *CircuitBreaker) Release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+}
+
+// CircuitBreakers holds a CircuitBreaker per exchange, created on demand with the same settings
+type CircuitBreakers struct {
+	mu               sync.Mutex
+	failureThreshold int
+	cooldown         time.Duration
+	breakers         map[string]*CircuitBreaker
+}
+
+// NewCircuitBreakers returns a new instance of CircuitBreakers
+func NewCircuitBreakers(failureThreshold int, cooldown time.Duration) *CircuitBreakers {
+	return &CircuitBreakers{
+		failureThreshold: failureThreshold,
+		cooldown:         cooldown,
+		breakers:         make(map[string]*CircuitBreaker),
+	}
+}
+
+// For returns the breaker of the exchange
+func (cb *CircuitBreakers) For(exchange string) *CircuitBreaker {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	exchange = strings.ToLower(exchange)
+
+	breaker, ok := cb.breakers[exchange]
+	if !ok {
+		breaker = NewCircuitBreaker(exchange, cb.failureThreshold, cb.cooldown)
+		cb.breakers[exchange] = breaker
+	}
+
+	return breaker
+}
@@ -0,0 +1,83 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	t.Run("Opens after the failure threshold", func(t *testing.T) {
+		breaker := NewCircuitBreaker("uphold", 3, time.Minute)
+
+		assert.False(t, breaker.Failure())
+		assert.False(t, breaker.Failure())
+		assert.True(t, breaker.Failure())
+
+		assert.Equal(t, BreakerOpen, breaker.State())
+		assert.False(t, breaker.Allow())
+		assert.False(t, breaker.Failure(), "an open breaker is only reported once")
+	})
+
+	t.Run("Success resets the failures count", func(t *testing.T) {
+		breaker := NewCircuitBreaker("uphold", 2, time.Minute)
+
+		assert.False(t, breaker.Failure())
+		assert.False(t, breaker.Success())
+		assert.False(t, breaker.Failure())
+
+		assert.Equal(t, BreakerClosed, breaker.State())
+	})
+
+	t.Run("Allows a single probe after the cooldown", func(t *testing.T) {
+		breaker := NewCircuitBreaker("uphold", 1, 20*time.Millisecond)
+
+		assert.True(t, breaker.Failure())
+
+		time.Sleep(30 * time.Millisecond)
+
+		assert.True(t, breaker.Allow())
+		assert.Equal(t, BreakerHalfOpen, breaker.State())
+		assert.False(t, breaker.Allow(), "only one probe at a time")
+
+		assert.True(t, breaker.Success())
+		assert.Equal(t, BreakerClosed, breaker.State())
+		assert.True(t, breaker.Allow())
+	})
+
+	t.Run("Failed probe opens the breaker again", func(t *testing.T) {
+		breaker := NewCircuitBreaker("uphold", 1, 20*time.Millisecond)
+
+		assert.True(t, breaker.Failure())
+
+		time.Sleep(30 * time.Millisecond)
+
+		assert.True(t, breaker.Allow())
+		assert.False(t, breaker.Failure())
+		assert.Equal(t, BreakerOpen, breaker.State())
+		assert.False(t, breaker.Allow())
+	})
+
+	t.Run("Released probe lets another one through", func(t *testing.T) {
+		breaker := NewCircuitBreaker("uphold", 1, 20*time.Millisecond)
+
+		assert.True(t, breaker.Failure())
+
+		time.Sleep(30 * time.Millisecond)
+
+		assert.True(t, breaker.Allow())
+		breaker.Release()
+
+		assert.Equal(t, BreakerHalfOpen, breaker.State())
+		assert.True(t, breaker.Allow())
+	})
+}
+
+func TestCircuitBreakers(t *testing.T) {
+	breakers := NewCircuitBreakers(1, time.Minute)
+
+	assert.Same(t, breakers.For("uphold"), breakers.For("Uphold"))
+	assert.NotSame(t, breakers.For("uphold"), breakers.For("kraken"))
+	assert.Equal(t, "kraken", breakers.For("kraken").Name())
+}
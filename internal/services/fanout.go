@@ -0,0 +1,99 @@
+package services
+
+import (
+	"crypto-alert-bot/internal/models"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=../mocks/mock_fanout/mock_$GOFILE
+type AlertPublisher interface {
+	Publish(*models.AlertView)
+}
+
+// FanOutPublisher publishes every alert concurrently to the channels its ticker is routed to,
+// tickers without routes are published to every channel
+type FanOutPublisher struct {
+	channels map[string]AlertPublisher
+	names    []string
+	timeout  time.Duration
+}
+
+// NewFanOutPublisher returns a new instance of FanOutPublisher that waits at most the timeout for each channel
+func NewFanOutPublisher(timeout time.Duration) *FanOutPublisher {
+	return &FanOutPublisher{
+		channels: make(map[string]AlertPublisher),
+		timeout:  timeout,
+	}
+}
+
+// Add registers the publisher under the channel name
+func (f *FanOutPublisher) Add(name string, publisher AlertPublisher) error {
+	if _, ok := f.channels[name]; ok {
+		return errors.Errorf("channel %q is already defined", name)
+	}
+
+	f.channels[name] = publisher
+	f.names = append(f.names, name)
+
+	return nil
+}
+
+// Has checks if a channel is registered under the name
+func (f *FanOutPublisher) Has(name string) bool {
+	_, ok := f.channels[name]
+
+	return ok
+}
+
+// Publish publishes the view of the ticker alert to each routed channel, a channel that fails or doesn't finish within
+// the timeout is logged and doesn't affect the others. The view is built before dispatch, so a channel still running
+// after its timeout never reads the ticker the scheduler keeps evaluating
+func (f *FanOutPublisher) Publish(timestamp time.Time, ticker *models.Ticker) {
+	names := ticker.Config.Channels
+	if len(names) == 0 {
+		names = f.names
+	}
+
+	alert := models.NewAlertView(timestamp, ticker)
+
+	var wg sync.WaitGroup
+
+	for _, name := range names {
+		publisher, ok := f.channels[name]
+		if !ok {
+			slog.Warn("alert routed to unknown channel", "channel", name, "pair", ticker.Pair)
+			continue
+		}
+
+		done := make(chan struct{})
+
+		go func() {
+			defer close(done)
+			defer func() {
+				if r := recover(); r != nil {
+					slog.Error("alert channel failed", "channel", name, "pair", alert.Pair, "error", r)
+				}
+			}()
+
+			publisher.Publish(alert)
+		}()
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			select {
+			case <-done:
+			case <-time.After(f.timeout):
+				slog.Warn("alert channel timed out", "channel", name, "pair", alert.Pair, "timeout", f.timeout)
+			}
+		}()
+	}
+
+	wg.Wait()
+}
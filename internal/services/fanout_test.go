@@ -0,0 +1,116 @@
+package services
+
+import (
+	"crypto-alert-bot/internal/mocks/mock_fanout"
+	"crypto-alert-bot/internal/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestFanOutPublisher(t *testing.T) {
+	tests := []struct {
+		name      string
+		routes    []string
+		published []string
+	}{
+		{
+			name:      "Routed ticker is published to its channels only",
+			routes:    []string{"slack", "log"},
+			published: []string{"slack", "log"},
+		},
+		{
+			name:      "Ticker without routes is published to every channel",
+			published: []string{"log", "slack", "webhook"},
+		},
+		{
+			name:      "Unknown channels are skipped",
+			routes:    []string{"email", "webhook"},
+			published: []string{"webhook"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			fanOut := NewFanOutPublisher(time.Second)
+
+			for _, name := range []string{"log", "slack", "webhook"} {
+				publisher := mock_services.NewMockAlertPublisher(ctrl)
+
+				times := 0
+				for _, published := range tt.published {
+					if published == name {
+						times = 1
+					}
+				}
+
+				publisher.EXPECT().Publish(gomock.Any()).Times(times)
+
+				assert.NoError(t, fanOut.Add(name, publisher))
+			}
+
+			ticker := models.NewTicker("BTCUSD", 5, 1, 0)
+			ticker.Config.Channels = tt.routes
+
+			fanOut.Publish(time.Now(), ticker)
+		})
+	}
+
+	t.Run("Failing and slow channels don't block the others", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		fanOut := NewFanOutPublisher(50 * time.Millisecond)
+
+		slow := mock_services.NewMockAlertPublisher(ctrl)
+		slow.EXPECT().Publish(gomock.Any()).Do(func(*models.AlertView) {
+			time.Sleep(time.Second)
+		})
+
+		failing := mock_services.NewMockAlertPublisher(ctrl)
+		failing.EXPECT().Publish(gomock.Any()).Do(func(*models.AlertView) {
+			panic("channel down")
+		})
+
+		received := make(chan *models.AlertView, 1)
+
+		healthy := mock_services.NewMockAlertPublisher(ctrl)
+		healthy.EXPECT().Publish(gomock.Any()).Do(func(alert *models.AlertView) {
+			received <- alert
+		})
+
+		assert.NoError(t, fanOut.Add("slow", slow))
+		assert.NoError(t, fanOut.Add("failing", failing))
+		assert.NoError(t, fanOut.Add("healthy", healthy))
+
+		ticker := models.NewTicker("BTCUSD", 5, 1, 0)
+		ticker.CurrentAsk = 100
+		ticker.Indicators = models.IndicatorValues{"RSI(14)": 72.5}
+
+		start := time.Now()
+		fanOut.Publish(time.Now(), ticker)
+
+		assert.Less(t, time.Since(start), 500*time.Millisecond)
+
+		ticker.CurrentAsk = 101
+		ticker.Indicators["RSI(14)"] = 30
+
+		got := <-received
+		assert.Equal(t, 100.0, got.Price, "channels receive a view detached from the ticker")
+		assert.Equal(t, models.IndicatorValues{"RSI(14)": 72.5}, got.Indicators)
+	})
+
+	t.Run("Channel names are unique", func(t *testing.T) {
+		fanOut := NewFanOutPublisher(time.Second)
+
+		assert.NoError(t, fanOut.Add("log", nil))
+		assert.Error(t, fanOut.Add("log", nil))
+		assert.True(t, fanOut.Has("log"))
+		assert.False(t, fanOut.Has("slack"))
+	})
+}
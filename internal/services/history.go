@@ -0,0 +1,13 @@
+package services
+
+import (
+	"context"
+
+	"crypto-alert-bot/internal/models"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=../mocks/mock_scheduler/mock_$GOFILE
+type AlertReader interface {
+	QueryAlerts(context.Context, models.AlertFilter, models.Page) (models.AlertPage, error)
+	AggregateAlerts(context.Context, models.AlertFilter, models.BucketSize) ([]models.AlertBucket, error)
+}
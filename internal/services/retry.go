@@ -0,0 +1,66 @@
+package services
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"net"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// RetryPolicy defines how many times a failed fetch is retried and how long to wait between attempts
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+	Jitter      float64
+}
+
+// DefaultRetryPolicy retries a failed fetch twice, waiting around 500ms and then 1s
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   500 * time.Millisecond,
+	MaxDelay:    5 * time.Second,
+	Jitter:      0.2,
+}
+
+// retryable is implemented by errors that know if the failed request can be retried
+type retryable interface {
+	Retryable() bool
+}
+
+// Backoff returns the exponential delay before the given retry attempt, randomized by the jitter fraction
+func (p RetryPolicy) Backoff(attempt int) time.Duration {
+	delay := float64(p.BaseDelay) * math.Pow(2, float64(attempt-1))
+	if p.MaxDelay > 0 && delay > float64(p.MaxDelay) {
+		delay = float64(p.MaxDelay)
+	}
+
+	if p.Jitter > 0 {
+		delay += delay * p.Jitter * (2*rand.Float64() - 1)
+	}
+
+	return time.Duration(delay)
+}
+
+// IsRetryable checks if a fetch error is worth retrying: network errors, timeouts, server errors and rate limited requests
+func IsRetryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	var retryableErr retryable
+	if errors.As(err, &retryableErr) {
+		return retryableErr.Retryable()
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	var netErr net.Error
+
+	return errors.As(err, &netErr)
+}
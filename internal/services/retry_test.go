@@ -0,0 +1,62 @@
+package services
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// statusError mimics the exchanges status errors
+type statusError struct {
+	retryable bool
+}
+
+func (e statusError) Error() string {
+	return "unexpected status code"
+}
+
+func (e statusError) Retryable() bool {
+	return e.retryable
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "No error", err: nil, want: false},
+		{name: "Network error", err: errors.Wrap(&net.OpError{Op: "dial", Err: errors.New("connection refused")}, "fetch"), want: true},
+		{name: "Timeout", err: errors.Wrap(context.DeadlineExceeded, "fetch"), want: true},
+		{name: "Canceled", err: context.Canceled, want: false},
+		{name: "Retryable status", err: errors.Wrap(statusError{retryable: true}, "fetch"), want: true},
+		{name: "Non retryable status", err: statusError{retryable: false}, want: false},
+		{name: "Parsing error", err: errors.New("error unmarshalling api response"), want: false},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsRetryable(tt.err))
+		})
+	}
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: 300 * time.Millisecond}
+
+	assert.Equal(t, 100*time.Millisecond, policy.Backoff(1))
+	assert.Equal(t, 200*time.Millisecond, policy.Backoff(2))
+	assert.Equal(t, 300*time.Millisecond, policy.Backoff(3))
+
+	policy.Jitter = 0.5
+	for i := 0; i < 20; i++ {
+		delay := policy.Backoff(1)
+		assert.GreaterOrEqual(t, delay, 50*time.Millisecond)
+		assert.LessOrEqual(t, delay, 150*time.Millisecond)
+	}
+}
@@ -15,6 +15,11 @@ type DataRetriever interface {
 	FetchPairData(context.Context, *models.Ticker) error
 }
 
+//go:generate mockgen -source=$GOFILE -destination=../mocks/mock_scheduler/mock_$GOFILE
+type QuoteStreamer interface {
+	Subscribe(context.Context, *models.Ticker) (<-chan models.Quote, error)
+}
+
 //go:generate mockgen -source=$GOFILE -destination=../mocks/mock_scheduler/mock_$GOFILE
 type Publisher interface {
 	Publish(time.Time, *models.Ticker)
@@ -27,70 +32,323 @@ type Recorder interface {
 
 // TickerScheduler represents the scheduler for the ticker, orchestrating the fetching of data and publishing of alerts
 type TickerScheduler struct {
-	api       DataRetriever
-	ticker    *models.Ticker
-	publisher Publisher
-	repo      Recorder
-	stop      chan struct{}
+	api         DataRetriever
+	ticker      *models.Ticker
+	publisher   Publisher
+	repo        Recorder
+	retryPolicy RetryPolicy
+	breaker     *CircuitBreaker
+	streamer    QuoteStreamer
+	watchdog    *Watchdog
+	stop        chan struct{}
+}
+
+// SchedulerOption customizes a TickerScheduler
+type SchedulerOption func(*TickerScheduler)
+
+// WithRetryPolicy sets how failed fetches are retried, DefaultRetryPolicy is used otherwise
+func WithRetryPolicy(policy RetryPolicy) SchedulerOption {
+	return func(ts *TickerScheduler) {
+		ts.retryPolicy = policy
+	}
 }
 
-// NewTickerScheduler returns a new instance of TickerScheduler
-func NewTickerScheduler(apiResponse DataRetriever, ticker *models.Ticker, repo Recorder, publisher Publisher) *TickerScheduler {
-	return &TickerScheduler{
-		api:       apiResponse,
-		ticker:    ticker,
-		publisher: publisher,
-		repo:      repo,
-		stop:      make(chan struct{}),
+// WithCircuitBreaker sets the breaker shared by the schedulers of the ticker exchange
+func WithCircuitBreaker(breaker *CircuitBreaker) SchedulerOption {
+	return func(ts *TickerScheduler) {
+		ts.breaker = breaker
+	}
+}
+
+// WithQuoteStream makes the scheduler evaluate the ticker on every streamed quote instead of polling at the refresh rate
+func WithQuoteStream(streamer QuoteStreamer) SchedulerOption {
+	return func(ts *TickerScheduler) {
+		ts.streamer = streamer
+	}
+}
+
+// NewTickerScheduler returns a new instance of TickerScheduler, watching for stale data when the ticker sets StaleAfter
+func NewTickerScheduler(apiResponse DataRetriever, ticker *models.Ticker, repo Recorder, publisher Publisher, opts ...SchedulerOption) *TickerScheduler {
+	ts := &TickerScheduler{
+		api:         apiResponse,
+		ticker:      ticker,
+		publisher:   publisher,
+		repo:        repo,
+		retryPolicy: DefaultRetryPolicy,
+		stop:        make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(ts)
+	}
+
+	if ticker.Config.StaleAfter > 0 {
+		ts.watchdog = NewWatchdog(ticker.Config.StaleAfter, time.Now())
 	}
+
+	return ts
 }
 
-// SchedulerStart starts the scheduler
+// SchedulerStart starts the scheduler, streaming the quotes when a streamer is set, polling otherwise
 func (ts *TickerScheduler) SchedulerStart(ctx context.Context) {
+	if ts.streamer != nil {
+		quotes, cancel, err := ts.subscribe(ctx)
+		if err == nil {
+			go ts.stream(ctx, quotes, cancel)
+			return
+		}
+
+		slog.Error("error subscribing to quote stream, falling back to polling", "pair", ts.ticker.Pair, "error", err)
+	}
+
 	interval := time.Duration((ts.ticker.Config.RefreshRate) * float64(time.Second))
 	timeTicker := time.NewTicker(interval)
 
 	go func() {
+		defer timeTicker.Stop()
+
 		for {
 			select {
 			case <-timeTicker.C:
-				apiCtx, cancel := context.WithTimeout(ctx, apiTimeout*time.Second)
-				defer cancel()
+				ts.tick(ctx)
+				ts.watch(time.Now())
 
-				err := ts.api.FetchPairData(apiCtx, ts.ticker)
-				if err != nil {
-					slog.Error("error fetching data", "error", err)
-					break
-				}
+			case <-ts.stop:
+				slog.Info("scheduler stopped", "pair", ts.ticker.Pair)
+				return
 
-				if !ts.ticker.IsAbovePercOscillation() {
-					break
-				}
+			case <-ctx.Done():
+				slog.Info("scheduler canceled by context")
+				return
+			}
+		}
+	}()
+}
 
-				dbCtx, dbCancel := context.WithTimeout(ctx, dbTimeout*time.Second)
-				defer dbCancel()
+// subscribe subscribes the ticker to the streamer, the subscription ends when the scheduler is stopped or the context is done
+func (ts *TickerScheduler) subscribe(ctx context.Context) (<-chan models.Quote, context.CancelFunc, error) {
+	streamCtx, cancel := context.WithCancel(ctx)
 
-				timestamp := time.Now().UTC()
+	quotes, err := ts.streamer.Subscribe(streamCtx, ts.ticker)
+	if err != nil {
+		cancel()
+		return nil, nil, err
+	}
 
-				ts.publisher.Publish(timestamp, ts.ticker)
+	return quotes, cancel, nil
+}
 
-				err = ts.repo.Save(dbCtx, timestamp, ts.ticker)
-				if err != nil {
-					slog.Error("error saving to database", "error", err)
-				}
+// stream evaluates the ticker on every quote until the subscription ends, checking for stale data at the refresh rate
+func (ts *TickerScheduler) stream(ctx context.Context, quotes <-chan models.Quote, cancel context.CancelFunc) {
+	defer cancel()
 
-				ts.ticker.NormalizeValues()
+	var watchTicks <-chan time.Time
+	if ts.watchdog != nil {
+		watchTicker := time.NewTicker(time.Duration(ts.ticker.Config.RefreshRate * float64(time.Second)))
+		defer watchTicker.Stop()
 
-			case <-ctx.Done():
-				slog.Info("scheduler canceled by context")
-				timeTicker.Stop()
+		watchTicks = watchTicker.C
+	}
+
+	for {
+		select {
+		case quote, ok := <-quotes:
+			if !ok {
+				slog.Info("quote stream closed", "pair", ts.ticker.Pair)
 				return
 			}
+
+			at := quote.Time
+			if at.IsZero() {
+				at = time.Now()
+			}
+
+			ts.ticker.ApplyQuote(quote)
+			ts.observe(time.Now())
+			ts.evaluate(ctx, at)
+
+		case now := <-watchTicks:
+			ts.watch(now)
+
+		case <-ts.stop:
+			slog.Info("scheduler stopped", "pair", ts.ticker.Pair)
+			return
+
+		case <-ctx.Done():
+			slog.Info("scheduler canceled by context")
+			return
 		}
-	}()
+	}
 }
 
 // SchedulerStop stops the scheduler
 func (ts *TickerScheduler) SchedulerStop() {
 	close(ts.stop)
 }
+
+// tick fetches the ticker data and evaluates it, while the exchange circuit breaker is open the fetch is skipped.
+// Only retryable errors count as exchange failures, an error specific to the pair doesn't pause the whole exchange
+func (ts *TickerScheduler) tick(ctx context.Context) {
+	if ts.breaker != nil && !ts.breaker.Allow() {
+		return
+	}
+
+	err := ts.fetch(ctx)
+	if err != nil {
+		slog.Error("error fetching data", "pair", ts.ticker.Pair, "error", err)
+
+		switch {
+		case ts.breaker == nil:
+		case !IsRetryable(err):
+			ts.breaker.Release()
+		case ts.breaker.Failure():
+			ts.publishSourceStatus(models.AlertSourceDown)
+		}
+
+		return
+	}
+
+	if ts.breaker != nil && ts.breaker.Success() {
+		ts.publishSourceStatus(models.AlertSourceUp)
+	}
+
+	now := time.Now()
+
+	ts.observe(now)
+	ts.evaluate(ctx, now)
+}
+
+// observe feeds the current quote received at the given time to the watchdog
+func (ts *TickerScheduler) observe(at time.Time) {
+	if ts.watchdog == nil {
+		return
+	}
+
+	quote := models.Quote{Ask: ts.ticker.CurrentAsk, Bid: ts.ticker.CurrentBid, Last: ts.ticker.CurrentLast}
+	if ts.watchdog.Observe(quote, at) {
+		slog.Info("quotes are fresh again", "pair", ts.ticker.Pair)
+	}
+}
+
+// watch publishes a stale data alert when the watchdog finds the ticker data stale at the given time
+func (ts *TickerScheduler) watch(at time.Time) {
+	if ts.watchdog == nil {
+		return
+	}
+
+	status, stale := ts.watchdog.Check(at)
+	if !stale {
+		return
+	}
+
+	ts.ticker.AlertType = models.AlertStale
+	ts.ticker.Rule = nil
+	ts.ticker.Stale = status
+
+	ts.publisher.Publish(time.Now().UTC(), ts.ticker)
+
+	ts.ticker.Stale = nil
+}
+
+// evaluate publishes and saves an alert for every price level crossed and every rule firing on the price observed at the given time
+func (ts *TickerScheduler) evaluate(ctx context.Context, at time.Time) {
+	defer ts.ticker.RecordReturn(at)
+
+	ts.ticker.RearmAlerts()
+	ts.ticker.RecordQuote(at)
+	ts.ticker.RecordBar(at)
+	ts.ticker.ShareQuote(at)
+
+	ts.ticker.Rule = nil
+
+	for _, level := range ts.ticker.CrossedLevels() {
+		ts.ticker.AlertType = models.AlertLevel
+		ts.ticker.Level = &level
+
+		ts.alert(ctx, at)
+	}
+
+	ts.ticker.Level = nil
+
+	for _, rule := range ts.ticker.Config.AlertRules() {
+		fired, err := rule.Evaluate(ts.ticker, at)
+		if err != nil {
+			slog.Warn("error evaluating rule", "pair", ts.ticker.Pair, "rule", rule.String(), "error", err)
+			continue
+		}
+
+		if !fired {
+			continue
+		}
+
+		ts.ticker.AlertType = rule.Type()
+		ts.ticker.Rule = rule
+
+		ts.alert(ctx, at)
+
+		ts.ticker.Divergence = nil
+		ts.ticker.Indicators = nil
+		ts.ticker.Peg = nil
+	}
+}
+
+// alert publishes and saves the ticker alert unless it's suppressed by the cooldown or re-arm rules,
+// the delivered alert carries the count of alerts suppressed since the previous one. A delivered threshold alert moves
+// the baseline to the alerted price, while a suppressed one leaves it at the price of the last delivered alert
+func (ts *TickerScheduler) alert(ctx context.Context, at time.Time) {
+	if !ts.ticker.ShouldAlert(at) {
+		slog.Debug("alert suppressed", "pair", ts.ticker.Pair, "type", ts.ticker.AlertType, "suppressed", ts.ticker.Suppressed)
+		return
+	}
+
+	dbCtx, dbCancel := context.WithTimeout(ctx, dbTimeout)
+	defer dbCancel()
+
+	timestamp := time.Now().UTC()
+
+	ts.publisher.Publish(timestamp, ts.ticker)
+
+	err := ts.repo.Save(dbCtx, timestamp, ts.ticker)
+	if err != nil {
+		slog.Error("error saving to database", "error", err)
+	}
+
+	ts.ticker.Suppressed = 0
+
+	if ts.ticker.AlertType == models.AlertThreshold {
+		ts.ticker.NormalizeValues()
+	}
+}
+
+// fetch fetches the ticker data, retrying retryable errors according to the retry policy
+func (ts *TickerScheduler) fetch(ctx context.Context) error {
+	var err error
+
+	for attempt := 1; ; attempt++ {
+		apiCtx, cancel := context.WithTimeout(ctx, apiTimeout)
+		err = ts.api.FetchPairData(apiCtx, ts.ticker)
+		cancel()
+
+		if err == nil || attempt >= ts.retryPolicy.MaxAttempts || !IsRetryable(err) || ctx.Err() != nil {
+			return err
+		}
+
+		slog.Warn("retrying fetch", "pair", ts.ticker.Pair, "attempt", attempt, "error", err)
+
+		select {
+		case <-time.After(ts.retryPolicy.Backoff(attempt)):
+		case <-ts.stop:
+			return err
+		case <-ctx.Done():
+			return err
+		}
+	}
+}
+
+// publishSourceStatus publishes that the ticker exchange went down or recovered
+func (ts *TickerScheduler) publishSourceStatus(alertType models.AlertType) {
+	ts.ticker.AlertType = alertType
+	ts.ticker.Rule = nil
+
+	ts.publisher.Publish(time.Now().UTC(), ts.ticker)
+}
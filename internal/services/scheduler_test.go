@@ -1,15 +1,15 @@
 package services
 
 import (
+	"context"
+	"crypto-alert-bot/internal/mocks/mock_scheduler"
 	"go.uber.org/mock/gomock"
 	"testing"
 	"time"
-	mock_prompt "uphold-alert-bot/internal/adapters/mocks/mock_api"
-	"uphold-alert-bot/internal/mocks/mock_scheduler"
 
 	"github.com/stretchr/testify/assert"
 
-	"uphold-alert-bot/internal/models"
+	"crypto-alert-bot/internal/models"
 )
 
 func TestTickerScheduler(t *testing.T) {
@@ -17,7 +17,7 @@ func TestTickerScheduler(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
-		mockAPI := mock_prompt.NewMockApiDataValidator(ctrl)
+		mockAPI := mock_services.NewMockDataRetriever(ctrl)
 		mockRepo := mock_services.NewMockRecorder(ctrl)
 		mockPublisher := mock_services.NewMockPublisher(ctrl)
 
@@ -31,16 +31,19 @@ func TestTickerScheduler(t *testing.T) {
 		testTicker.CurrentAsk = 102.0
 
 		mockAPI.EXPECT().
-			FetchPairData(testTicker).
+			FetchPairData(gomock.Any(), testTicker).
 			Return(nil).
 			AnyTimes()
 
+		ctx, cancel := context.WithCancel(context.Background())
+
 		sched := NewTickerScheduler(mockAPI, testTicker, mockRepo, mockPublisher)
 
-		sched.SchedulerStart()
+		sched.SchedulerStart(ctx)
 
 		time.Sleep(2 * time.Second)
 
+		cancel()
 		sched.SchedulerStop()
 
 		assert.Equal(t, float64(102.0), testTicker.CurrentAsk.Float64())
@@ -50,9 +53,9 @@ func TestTickerScheduler(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
-		mockAPI := mock_api.NewMockApiResponseInterface(ctrl)
-		mockRepo := mock_repository.NewMockRepository(ctrl)
-		mockPublisher := mock_services.NewMockPublisherInterface(ctrl)
+		mockAPI := mock_services.NewMockDataRetriever(ctrl)
+		mockRepo := mock_services.NewMockRecorder(ctrl)
+		mockPublisher := mock_services.NewMockPublisher(ctrl)
 
 		testTicker := &models.Ticker{
 			Config: models.TickerConfig{
@@ -64,7 +67,7 @@ func TestTickerScheduler(t *testing.T) {
 		}
 
 		mockAPI.EXPECT().
-			FetchPairData(testTicker).
+			FetchPairData(gomock.Any(), testTicker).
 			Return(nil).
 			AnyTimes()
 
@@ -73,15 +76,389 @@ func TestTickerScheduler(t *testing.T) {
 			Times(1)
 
 		mockRepo.EXPECT().
-			Save(gomock.Any(), testTicker).
+			Save(gomock.Any(), gomock.Any(), testTicker).
 			Return(nil).
 			Times(1)
 
+		ctx, cancel := context.WithCancel(context.Background())
+
 		sched := NewTickerScheduler(mockAPI, testTicker, mockRepo, mockPublisher)
-		sched.SchedulerStart()
+		sched.SchedulerStart(ctx)
 
-		time.Sleep(2 * time.Second)
+		time.Sleep(2500 * time.Millisecond)
 
+		cancel()
 		sched.SchedulerStop()
 	})
 }
+
+func TestTickerSchedulerTick(t *testing.T) {
+	fastRetries := WithRetryPolicy(RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond})
+
+	t.Run("Retries retryable errors", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockAPI := mock_services.NewMockDataRetriever(ctrl)
+		mockRepo := mock_services.NewMockRecorder(ctrl)
+		mockPublisher := mock_services.NewMockPublisher(ctrl)
+
+		testTicker := &models.Ticker{Config: models.TickerConfig{RefreshRate: 1, PercOscillation: 5.0}}
+
+		gomock.InOrder(
+			mockAPI.EXPECT().FetchPairData(gomock.Any(), testTicker).Return(context.DeadlineExceeded).Times(2),
+			mockAPI.EXPECT().FetchPairData(gomock.Any(), testTicker).Return(nil).Times(1),
+		)
+
+		sched := NewTickerScheduler(mockAPI, testTicker, mockRepo, mockPublisher, fastRetries)
+		sched.tick(context.Background())
+	})
+
+	t.Run("Doesn't retry non retryable errors", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockAPI := mock_services.NewMockDataRetriever(ctrl)
+		mockRepo := mock_services.NewMockRecorder(ctrl)
+		mockPublisher := mock_services.NewMockPublisher(ctrl)
+
+		testTicker := &models.Ticker{Config: models.TickerConfig{RefreshRate: 1, PercOscillation: 5.0}}
+
+		mockAPI.EXPECT().FetchPairData(gomock.Any(), testTicker).Return(statusError{retryable: false}).Times(1)
+
+		sched := NewTickerScheduler(mockAPI, testTicker, mockRepo, mockPublisher, fastRetries)
+		sched.tick(context.Background())
+	})
+
+	t.Run("Publishes when the data source goes down and recovers", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockAPI := mock_services.NewMockDataRetriever(ctrl)
+		mockRepo := mock_services.NewMockRecorder(ctrl)
+		mockPublisher := mock_services.NewMockPublisher(ctrl)
+
+		testTicker := &models.Ticker{Pair: "BTCUSD", Config: models.TickerConfig{RefreshRate: 1, PercOscillation: 5.0}}
+		breaker := NewCircuitBreaker("uphold", 2, 20*time.Millisecond)
+
+		var published []models.AlertType
+
+		gomock.InOrder(
+			mockAPI.EXPECT().FetchPairData(gomock.Any(), testTicker).Return(statusError{retryable: true}).Times(3),
+			mockAPI.EXPECT().FetchPairData(gomock.Any(), testTicker).Return(nil).Times(1),
+		)
+		mockPublisher.EXPECT().Publish(gomock.Any(), testTicker).Do(func(_ time.Time, ticker *models.Ticker) {
+			published = append(published, ticker.AlertType)
+		}).Times(2)
+
+		sched := NewTickerScheduler(mockAPI, testTicker, mockRepo, mockPublisher,
+			WithRetryPolicy(RetryPolicy{MaxAttempts: 1}), WithCircuitBreaker(breaker))
+
+		sched.tick(context.Background())
+		sched.tick(context.Background())
+		assert.Equal(t, BreakerOpen, breaker.State())
+
+		sched.tick(context.Background())
+
+		time.Sleep(30 * time.Millisecond)
+
+		sched.tick(context.Background())
+		assert.Equal(t, BreakerOpen, breaker.State(), "failed probe")
+
+		time.Sleep(30 * time.Millisecond)
+
+		sched.tick(context.Background())
+		assert.Equal(t, BreakerClosed, breaker.State())
+
+		assert.Equal(t, []models.AlertType{models.AlertSourceDown, models.AlertSourceUp}, published)
+	})
+
+	t.Run("Pair errors don't open the exchange breaker", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockAPI := mock_services.NewMockDataRetriever(ctrl)
+		mockRepo := mock_services.NewMockRecorder(ctrl)
+		mockPublisher := mock_services.NewMockPublisher(ctrl)
+
+		testTicker := &models.Ticker{Pair: "BTCUSX", Config: models.TickerConfig{RefreshRate: 1, PercOscillation: 5.0}}
+		breaker := NewCircuitBreaker("uphold", 2, 20*time.Millisecond)
+
+		mockAPI.EXPECT().FetchPairData(gomock.Any(), testTicker).Return(statusError{retryable: false}).Times(3)
+		mockPublisher.EXPECT().Publish(gomock.Any(), gomock.Any()).Times(0)
+
+		sched := NewTickerScheduler(mockAPI, testTicker, mockRepo, mockPublisher,
+			WithRetryPolicy(RetryPolicy{MaxAttempts: 1}), WithCircuitBreaker(breaker))
+
+		for i := 0; i < 3; i++ {
+			sched.tick(context.Background())
+		}
+
+		assert.Equal(t, BreakerClosed, breaker.State())
+	})
+}
+
+func TestTickerSchedulerWatchdog(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAPI := mock_services.NewMockDataRetriever(ctrl)
+	mockRepo := mock_services.NewMockRecorder(ctrl)
+	mockPublisher := mock_services.NewMockPublisher(ctrl)
+
+	testTicker := &models.Ticker{Pair: "BTCUSD", Config: models.TickerConfig{RefreshRate: 1, PercOscillation: 5.0, StaleAfter: time.Minute}}
+
+	mockAPI.EXPECT().FetchPairData(gomock.Any(), testTicker).DoAndReturn(func(_ context.Context, ticker *models.Ticker) error {
+		ticker.CurrentAsk = 100
+		return nil
+	}).Times(2)
+
+	var published []models.StaleStatus
+	mockPublisher.EXPECT().Publish(gomock.Any(), testTicker).Do(func(_ time.Time, ticker *models.Ticker) {
+		assert.Equal(t, models.AlertStale, ticker.AlertType)
+		published = append(published, *ticker.Stale)
+	}).Times(1)
+
+	sched := NewTickerScheduler(mockAPI, testTicker, mockRepo, mockPublisher)
+
+	sched.tick(context.Background())
+	sched.watch(time.Now())
+
+	sched.tick(context.Background())
+	sched.watch(time.Now().Add(2 * time.Minute))
+	sched.watch(time.Now().Add(3 * time.Minute))
+
+	assert.Len(t, published, 1)
+	assert.Equal(t, models.StaleNoData, published[0].Reason)
+	assert.Nil(t, testTicker.Stale)
+}
+
+func TestTickerSchedulerLevels(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAPI := mock_services.NewMockDataRetriever(ctrl)
+	mockRepo := mock_services.NewMockRecorder(ctrl)
+	mockPublisher := mock_services.NewMockPublisher(ctrl)
+
+	testTicker := &models.Ticker{Pair: "BTCUSD", Config: models.TickerConfig{
+		RefreshRate: 1,
+		Direction:   models.DirectionNone,
+		Levels:      []models.PriceLevel{{Target: 70000, Direction: models.DirectionUp}},
+	}}
+
+	asks := []models.Float64{69000, 70500, 71000, 69500, 70100}
+	fetch := 0
+
+	mockAPI.EXPECT().FetchPairData(gomock.Any(), testTicker).DoAndReturn(func(_ context.Context, ticker *models.Ticker) error {
+		ticker.CurrentAsk = asks[fetch]
+		fetch++
+		return nil
+	}).Times(len(asks))
+
+	var crossed []float64
+
+	mockPublisher.EXPECT().Publish(gomock.Any(), testTicker).Do(func(_ time.Time, ticker *models.Ticker) {
+		assert.Equal(t, models.AlertLevel, ticker.AlertType)
+		assert.Equal(t, 70000.0, ticker.Level.Target)
+		crossed = append(crossed, ticker.CurrentAsk.Float64())
+	}).Times(2)
+	mockRepo.EXPECT().Save(gomock.Any(), gomock.Any(), testTicker).Return(nil).Times(2)
+
+	sched := NewTickerScheduler(mockAPI, testTicker, mockRepo, mockPublisher)
+
+	for range asks {
+		sched.tick(context.Background())
+	}
+
+	assert.Equal(t, []float64{70500, 70100}, crossed, "fires once per cross above")
+}
+
+func TestTickerSchedulerSuppression(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAPI := mock_services.NewMockDataRetriever(ctrl)
+	mockRepo := mock_services.NewMockRecorder(ctrl)
+	mockPublisher := mock_services.NewMockPublisher(ctrl)
+
+	testTicker := models.NewTicker("BTCUSD", 1, 1, 0)
+	testTicker.Config.Rearm = 1
+
+	asks := []models.Float64{100, 102, 104, 106, 103, 100.5, 99, 103}
+	fetch := 0
+
+	mockAPI.EXPECT().FetchPairData(gomock.Any(), testTicker).DoAndReturn(func(_ context.Context, ticker *models.Ticker) error {
+		ticker.CurrentAsk = asks[fetch]
+		fetch++
+		return nil
+	}).Times(len(asks))
+
+	type delivered struct {
+		ask        float64
+		direction  models.Direction
+		suppressed int
+	}
+
+	var alerts []delivered
+
+	mockPublisher.EXPECT().Publish(gomock.Any(), testTicker).Do(func(_ time.Time, ticker *models.Ticker) {
+		alerts = append(alerts, delivered{ticker.CurrentAsk.Float64(), ticker.ChangeDirection(), ticker.Suppressed})
+	}).Times(3)
+	mockRepo.EXPECT().Save(gomock.Any(), gomock.Any(), testTicker).Return(nil).Times(3)
+
+	sched := NewTickerScheduler(mockAPI, testTicker, mockRepo, mockPublisher)
+
+	for range asks {
+		sched.tick(context.Background())
+	}
+
+	assert.Equal(t, []delivered{
+		{ask: 102, direction: models.DirectionUp},
+		{ask: 100.5, direction: models.DirectionDown, suppressed: 2},
+		{ask: 103, direction: models.DirectionUp, suppressed: 1},
+	}, alerts, "suppressed alerts keep the baseline of the last delivered one, rises fire again once re-armed carrying the suppressed count")
+	assert.Zero(t, testTicker.Suppressed)
+}
+
+func TestTickerSchedulerRules(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAPI := mock_services.NewMockDataRetriever(ctrl)
+	mockRepo := mock_services.NewMockRecorder(ctrl)
+	mockPublisher := mock_services.NewMockPublisher(ctrl)
+
+	rule, err := models.NewExpressionRule("spread_pct > 1")
+	assert.NoError(t, err)
+
+	testTicker := models.NewTicker("BTCUSD", 1, 5, 0)
+	testTicker.Config.Rules = []models.Rule{models.ThresholdRule{}, rule}
+
+	quotes := [][2]models.Float64{{100, 99.5}, {100, 98}, {106, 105.5}}
+	fetch := 0
+
+	mockAPI.EXPECT().FetchPairData(gomock.Any(), testTicker).DoAndReturn(func(_ context.Context, ticker *models.Ticker) error {
+		ticker.CurrentAsk, ticker.CurrentBid = quotes[fetch][0], quotes[fetch][1]
+		fetch++
+		return nil
+	}).Times(len(quotes))
+
+	var alerts []models.AlertType
+
+	mockPublisher.EXPECT().Publish(gomock.Any(), testTicker).Do(func(_ time.Time, ticker *models.Ticker) {
+		alerts = append(alerts, ticker.AlertType)
+		assert.Equal(t, ticker.AlertType, ticker.Rule.Type())
+	}).Times(2)
+	mockRepo.EXPECT().Save(gomock.Any(), gomock.Any(), testTicker).Return(nil).Times(2)
+
+	sched := NewTickerScheduler(mockAPI, testTicker, mockRepo, mockPublisher)
+
+	for range quotes {
+		sched.tick(context.Background())
+	}
+
+	assert.Equal(t, []models.AlertType{models.AlertExpression, models.AlertThreshold}, alerts)
+}
+
+func TestTickerSchedulerRulesDirection(t *testing.T) {
+	expression, err := models.NewExpressionRule("spread_pct > 1")
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name string
+		rule models.Rule
+	}{
+		{name: "After an expression alert", rule: expression},
+		{name: "After a spread alert", rule: &models.SpreadRule{MaxPct: 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockAPI := mock_services.NewMockDataRetriever(ctrl)
+			mockRepo := mock_services.NewMockRecorder(ctrl)
+			mockPublisher := mock_services.NewMockPublisher(ctrl)
+
+			testTicker := models.NewTicker("BTCUSD", 1, 5, 0)
+			testTicker.Config.Direction = models.DirectionUp
+			testTicker.Config.Rules = []models.Rule{models.ThresholdRule{}, tt.rule}
+
+			quotes := [][2]models.Float64{{100, 99.5}, {100, 98}, {106, 105.5}}
+			fetch := 0
+
+			mockAPI.EXPECT().FetchPairData(gomock.Any(), testTicker).DoAndReturn(func(_ context.Context, ticker *models.Ticker) error {
+				ticker.CurrentAsk, ticker.CurrentBid = quotes[fetch][0], quotes[fetch][1]
+				fetch++
+				return nil
+			}).Times(len(quotes))
+
+			var alerts []models.AlertType
+
+			mockPublisher.EXPECT().Publish(gomock.Any(), testTicker).Do(func(_ time.Time, ticker *models.Ticker) {
+				alerts = append(alerts, ticker.AlertType)
+			}).Times(2)
+			mockRepo.EXPECT().Save(gomock.Any(), gomock.Any(), testTicker).Return(nil).Times(2)
+
+			sched := NewTickerScheduler(mockAPI, testTicker, mockRepo, mockPublisher)
+
+			for range quotes {
+				sched.tick(context.Background())
+			}
+
+			assert.Equal(t, []models.AlertType{tt.rule.Type(), models.AlertThreshold}, alerts, "the previous alert type doesn't block an up-only threshold")
+		})
+	}
+}
+
+func TestTickerSchedulerStream(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAPI := mock_services.NewMockDataRetriever(ctrl)
+	mockStreamer := mock_services.NewMockQuoteStreamer(ctrl)
+	mockRepo := mock_services.NewMockRecorder(ctrl)
+	mockPublisher := mock_services.NewMockPublisher(ctrl)
+
+	testTicker := &models.Ticker{
+		Pair:   "BTC-USD",
+		Config: models.TickerConfig{RefreshRate: 60, PercOscillation: 5.0, Stream: true},
+	}
+
+	quotes := make(chan models.Quote)
+	published := make(chan float64, 1)
+
+	mockStreamer.EXPECT().Subscribe(gomock.Any(), testTicker).Return((<-chan models.Quote)(quotes), nil)
+	mockPublisher.EXPECT().Publish(gomock.Any(), gomock.Any()).Do(func(_ time.Time, ticker *models.Ticker) {
+		published <- ticker.CurrentAsk.Float64()
+	}).Times(1)
+	type ctxKey struct{}
+
+	saved := make(chan context.Context, 1)
+	mockRepo.EXPECT().Save(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(ctx context.Context, _ time.Time, _ *models.Ticker) {
+		saved <- ctx
+	}).Return(nil).Times(1)
+
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), ctxKey{}, "scheduler"))
+	defer cancel()
+
+	sched := NewTickerScheduler(mockAPI, testTicker, mockRepo, mockPublisher, WithQuoteStream(mockStreamer))
+	sched.SchedulerStart(ctx)
+
+	quotes <- models.Quote{Ask: 100, Bid: 99}
+	quotes <- models.Quote{Ask: 102, Bid: 101}
+	quotes <- models.Quote{Ask: 110, Bid: 109}
+
+	select {
+	case ask := <-published:
+		assert.Equal(t, 110.0, ask)
+	case <-time.After(time.Second):
+		t.Fatal("alert was not published")
+	}
+
+	assert.Equal(t, "scheduler", (<-saved).Value(ctxKey{}), "saves are bound to the scheduler context")
+
+	sched.SchedulerStop()
+}
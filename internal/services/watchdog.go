@@ -0,0 +1,71 @@
+package services
+
+import (
+	"time"
+
+	"crypto-alert-bot/internal/models"
+)
+
+// Watchdog detects the stale data of a ticker, when no quote was received or the received quotes didn't change for a duration.
+// Every stale episode is reported once, and the watchdog re-arms once the episode ends
+type Watchdog struct {
+	after    time.Duration
+	quote    models.Quote
+	received time.Time
+	changed  time.Time
+	episode  models.StaleReason
+}
+
+// NewWatchdog returns a new instance of Watchdog reporting the data as stale after the duration, counted from the start time
+func NewWatchdog(after time.Duration, start time.Time) *Watchdog {
+	return &Watchdog{after: after, received: start, changed: start}
+}
+
+// Observe records the quote received at the given time, it returns true when the quote ends a stale episode.
+// Any quote ends a no_data episode, the quotes being counted as frozen from then on, while only a different quote ends a frozen one
+func (w *Watchdog) Observe(quote models.Quote, at time.Time) bool {
+	w.received = at
+
+	changed := quote.Ask != w.quote.Ask || quote.Bid != w.quote.Bid || quote.Last != w.quote.Last
+	if changed {
+		w.quote = quote
+		w.changed = at
+	}
+
+	switch w.episode {
+	case models.StaleNoData:
+		if quote.Ask == 0 && quote.Bid == 0 && quote.Last == 0 {
+			return false
+		}
+
+		w.changed = at
+	case models.StaleFrozen:
+		if !changed {
+			return false
+		}
+	default:
+		return false
+	}
+
+	w.episode = ""
+
+	return true
+}
+
+// Check checks if the data is stale at the given time, reporting why only once per stale episode
+func (w *Watchdog) Check(at time.Time) (*models.StaleStatus, bool) {
+	if w.episode != "" {
+		return nil, false
+	}
+
+	switch {
+	case at.Sub(w.received) >= w.after:
+		w.episode = models.StaleNoData
+		return &models.StaleStatus{Reason: models.StaleNoData, Since: w.received}, true
+	case at.Sub(w.changed) >= w.after:
+		w.episode = models.StaleFrozen
+		return &models.StaleStatus{Reason: models.StaleFrozen, Since: w.changed}, true
+	default:
+		return nil, false
+	}
+}
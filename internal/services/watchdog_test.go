@@ -0,0 +1,87 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"crypto-alert-bot/internal/models"
+)
+
+func TestWatchdog(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	quote := models.Quote{Ask: 101, Bid: 100}
+
+	t.Run("Reports quotes received unchanged", func(t *testing.T) {
+		watchdog := NewWatchdog(5*time.Minute, start)
+
+		assert.False(t, watchdog.Observe(quote, start.Add(time.Minute)))
+		assert.False(t, watchdog.Observe(quote, start.Add(5*time.Minute)))
+
+		_, stale := watchdog.Check(start.Add(5 * time.Minute))
+		assert.False(t, stale)
+
+		assert.False(t, watchdog.Observe(quote, start.Add(6*time.Minute)))
+
+		status, stale := watchdog.Check(start.Add(6 * time.Minute))
+		assert.True(t, stale)
+		assert.Equal(t, &models.StaleStatus{Reason: models.StaleFrozen, Since: start.Add(time.Minute)}, status)
+
+		_, stale = watchdog.Check(start.Add(7 * time.Minute))
+		assert.False(t, stale, "a stale episode is only reported once")
+
+		assert.True(t, watchdog.Observe(models.Quote{Ask: 102, Bid: 100}, start.Add(8*time.Minute)), "a different quote ends the episode")
+	})
+
+	t.Run("Reports no quote received", func(t *testing.T) {
+		watchdog := NewWatchdog(5*time.Minute, start)
+
+		status, stale := watchdog.Check(start.Add(5 * time.Minute))
+		assert.True(t, stale)
+		assert.Equal(t, &models.StaleStatus{Reason: models.StaleNoData, Since: start}, status)
+
+		assert.False(t, watchdog.Observe(models.Quote{}, start.Add(6*time.Minute)), "an empty quote isn't a change")
+		assert.True(t, watchdog.Observe(quote, start.Add(7*time.Minute)))
+
+		_, stale = watchdog.Check(start.Add(11 * time.Minute))
+		assert.False(t, stale)
+
+		status, stale = watchdog.Check(start.Add(12 * time.Minute))
+		assert.True(t, stale)
+		assert.Equal(t, models.StaleNoData, status.Reason)
+	})
+
+	t.Run("The same quote resuming ends an outage", func(t *testing.T) {
+		watchdog := NewWatchdog(5*time.Minute, start)
+
+		assert.False(t, watchdog.Observe(quote, start.Add(time.Minute)))
+
+		status, stale := watchdog.Check(start.Add(6 * time.Minute))
+		assert.True(t, stale)
+		assert.Equal(t, models.StaleNoData, status.Reason)
+
+		assert.True(t, watchdog.Observe(quote, start.Add(7*time.Minute)), "any quote ends a no_data episode")
+
+		_, stale = watchdog.Check(start.Add(8 * time.Minute))
+		assert.False(t, stale, "the unchanged quote is counted as frozen from its resumption")
+
+		status, stale = watchdog.Check(start.Add(12 * time.Minute))
+		assert.True(t, stale, "a second outage is reported")
+		assert.Equal(t, &models.StaleStatus{Reason: models.StaleNoData, Since: start.Add(7 * time.Minute)}, status)
+	})
+
+	t.Run("Only a different quote ends a freeze", func(t *testing.T) {
+		watchdog := NewWatchdog(5*time.Minute, start)
+
+		assert.False(t, watchdog.Observe(quote, start.Add(time.Minute)))
+		assert.False(t, watchdog.Observe(quote, start.Add(6*time.Minute)))
+
+		status, stale := watchdog.Check(start.Add(6 * time.Minute))
+		assert.True(t, stale)
+		assert.Equal(t, models.StaleFrozen, status.Reason)
+
+		assert.False(t, watchdog.Observe(quote, start.Add(7*time.Minute)), "the same quote doesn't end a freeze")
+		assert.True(t, watchdog.Observe(models.Quote{Ask: 102, Bid: 100}, start.Add(8*time.Minute)))
+	})
+}
@@ -0,0 +1 @@
+ALTER TABLE ${schema}.${table_configs} ADD COLUMN zscore NUMERIC(10, 5) NOT NULL DEFAULT 0;
@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS ${table_alerts}_pair_timestamp_idx ON ${schema}.${table_alerts} (pair, timestamp DESC);
@@ -0,0 +1,11 @@
+CREATE TABLE ${schema}.webhook_deliveries (
+      id SERIAL PRIMARY KEY,
+      endpoint TEXT NOT NULL,
+      pair VARCHAR(20) NOT NULL,
+      alert_type VARCHAR(20) NOT NULL,
+      status VARCHAR(20) NOT NULL,
+      attempts INT NOT NULL,
+      status_code INT,
+      error TEXT,
+      timestamp TIMESTAMP NOT NULL
+);
@@ -0,0 +1 @@
+ALTER TABLE ${schema}.${table_alerts} ADD COLUMN price_source VARCHAR(4) NOT NULL DEFAULT 'ask';
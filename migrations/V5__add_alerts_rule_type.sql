@@ -0,0 +1,2 @@
+ALTER TABLE ${schema}.${table_alerts} ADD COLUMN rule_type VARCHAR(20) NOT NULL DEFAULT 'threshold';
+ALTER TABLE ${schema}.${table_alerts} ADD COLUMN target NUMERIC(30, 20);
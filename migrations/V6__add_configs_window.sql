@@ -0,0 +1 @@
+ALTER TABLE ${schema}.${table_configs} ADD COLUMN window_seconds INT NOT NULL DEFAULT 0;
@@ -0,0 +1 @@
+ALTER TABLE ${schema}.${table_alerts} ADD COLUMN suppressed INT NOT NULL DEFAULT 0;
@@ -0,0 +1 @@
+ALTER TABLE ${schema}.${table_alerts} ADD COLUMN expression TEXT;
@@ -0,0 +1,2 @@
+ALTER TABLE ${schema}.${table_alerts} ADD COLUMN ask NUMERIC(30, 20);
+ALTER TABLE ${schema}.${table_alerts} ADD COLUMN bid NUMERIC(30, 20);
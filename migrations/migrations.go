@@ -0,0 +1,8 @@
+package migrations
+
+import "embed"
+
+// FS holds the SQL migration scripts, named V<version>__<description>.sql, embedded into the bot binary
+//
+//go:embed *.sql
+var FS embed.FS